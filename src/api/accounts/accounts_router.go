@@ -5,6 +5,8 @@ import (
 	repository_accounts "app/infrascture/database/postgres/repository/accounts"
 	usecase_accounts "app/usecase/accounts"
 	"app/utils/token"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -18,6 +20,11 @@ type RegisterInput struct {
 	Name     string `json:"name" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=6"`
+	TimeZone string `json:"time_zone"`
+}
+
+type TimeZoneInput struct {
+	TimeZone string `json:"time_zone" binding:"required"`
 }
 
 type LoginInput struct {
@@ -26,17 +33,23 @@ type LoginInput struct {
 }
 
 type UserPixInput struct {
-	PixKey string `json:"pix_key" binding:"required"`
+	PixKey    string `json:"pix_key" binding:"required"`
+	KeyType   string `json:"key_type"`
+	IsPrimary bool   `json:"is_primary"`
 }
 
 type UserDayOffInput struct {
 	InitHour    time.Time `json:"init_hour" binding:"required"`
 	EndHour     time.Time `json:"end_hour" binding:"required"`
+	RRule       string    `json:"rrule"`
 	Repeat      bool      `json:"repeat"`
 	RepeatType  string    `json:"repeat_type"`
 	RepeatValue string    `json:"repeat_value"`
+	TimeZone    string    `json:"time_zone"`
 }
 
+const maxCalendarImportSize = 2 << 20
+
 type accountsRouter struct {
 	usecase_user        usecase_accounts.IUseCaseUser
 	usecase_user_pix    usecase_accounts.IUseCaseUserPix
@@ -59,9 +72,10 @@ func (ar *accountsRouter) Register(c *gin.Context) {
 	}
 
 	user := entity_accounts.User{
-		Name:  input.Name,
-		Email: input.Email,
-		Role:  entity_accounts.ROLE_USER,
+		Name:     input.Name,
+		Email:    input.Email,
+		Role:     entity_accounts.ROLE_USER,
+		TimeZone: input.TimeZone,
 	}
 
 	if err := user.EncryptedPassword(input.Password); err != nil {
@@ -70,7 +84,12 @@ func (ar *accountsRouter) Register(c *gin.Context) {
 	}
 
 	if err := ar.usecase_user.Register(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create user"})
+		switch {
+		case errors.Is(err, usecase_accounts.ErrInvalidTimeZone):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create user"})
+		}
 		return
 	}
 
@@ -112,11 +131,78 @@ func (ar *accountsRouter) GetMe(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"name": user.Name,
-		"role": user.Role,
+		"name":      user.Name,
+		"role":      user.Role,
+		"time_zone": user.TimeZone,
 	})
 }
 
+func (ar *accountsRouter) SetTimeZone(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input TimeZoneInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := ar.usecase_user.SetTimeZone(userId, input.TimeZone); err != nil {
+		switch {
+		case errors.Is(err, usecase_accounts.ErrInvalidTimeZone):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Time zone updated successfully"})
+}
+
+func calendarTokenResponse(calendarToken string) gin.H {
+	return gin.H{
+		"token":    calendarToken,
+		"user_url": "/api/user/calendar.ics?token=" + calendarToken,
+		"crew_url": "/api/crew/:id/calendar.ics?token=" + calendarToken,
+	}
+}
+
+func (ar *accountsRouter) GetCalendarToken(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	calendarToken, err := ar.usecase_user.GetCalendarToken(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarTokenResponse(calendarToken))
+}
+
+func (ar *accountsRouter) ResetCalendarToken(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	calendarToken, err := ar.usecase_user.ResetCalendarToken(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarTokenResponse(calendarToken))
+}
+
 func (ar *accountsRouter) CreatePix(c *gin.Context) {
 	userId, err := token.ExtractTokenID(c)
 	if err != nil {
@@ -131,10 +217,17 @@ func (ar *accountsRouter) CreatePix(c *gin.Context) {
 	}
 
 	userPix := entity_accounts.UserPix{
-		PixKey: input.PixKey,
+		PixKey:    input.PixKey,
+		KeyType:   input.KeyType,
+		IsPrimary: input.IsPrimary,
 	}
 
 	if err := ar.usecase_user_pix.Create(&userPix, userId); err != nil {
+		var fieldErrors usecase_accounts.FieldErrors
+		if errors.As(err, &fieldErrors) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pix key", "fields": fieldErrors})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -181,6 +274,49 @@ func (ar *accountsRouter) ListPix(c *gin.Context) {
 	c.JSON(http.StatusOK, userPixs)
 }
 
+func (ar *accountsRouter) UpdatePix(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input UserPixInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userPix := entity_accounts.UserPix{
+		ID:        &id,
+		PixKey:    input.PixKey,
+		KeyType:   input.KeyType,
+		IsPrimary: input.IsPrimary,
+	}
+
+	if err := ar.usecase_user_pix.Update(&userPix, userId); err != nil {
+		var fieldErrors usecase_accounts.FieldErrors
+		switch {
+		case errors.As(err, &fieldErrors):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pix key", "fields": fieldErrors})
+		case errors.Is(err, usecase_accounts.ErrPixNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, userPix)
+}
+
 func (ar *accountsRouter) DeletePix(c *gin.Context) {
 	userId, err := token.ExtractTokenID(c)
 	if err != nil {
@@ -203,6 +339,32 @@ func (ar *accountsRouter) DeletePix(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Pix key deleted successfully"})
 }
 
+// occurrenceQuery reads the start of the occurrence of a series to update or
+// delete, its recurrence_id. It answers 400 itself when the value is invalid.
+func occurrenceQuery(c *gin.Context) (*time.Time, bool) {
+	value := c.Query("occurrence")
+	if value == "" {
+		return nil, true
+	}
+	occurrence, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid occurrence parameter (must be RFC 3339)"})
+		return nil, false
+	}
+	return &occurrence, true
+}
+
+func dayOffErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase_accounts.ErrInvalidRecurrence), errors.Is(err, usecase_accounts.ErrNotRecurring), errors.Is(err, usecase_accounts.ErrInvalidTimeZone):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase_accounts.ErrDayOffNotFound), errors.Is(err, usecase_accounts.ErrOccurrenceNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (ar *accountsRouter) CreateDayOff(c *gin.Context) {
 	userId, err := token.ExtractTokenID(c)
 	if err != nil {
@@ -219,13 +381,15 @@ func (ar *accountsRouter) CreateDayOff(c *gin.Context) {
 	dayOff := entity_accounts.UserDayOff{
 		InitHour:    &input.InitHour,
 		EndHour:     &input.EndHour,
+		RRule:       input.RRule,
 		Repeat:      input.Repeat,
 		RepeatType:  input.RepeatType,
 		RepeatValue: input.RepeatValue,
+		TimeZone:    input.TimeZone,
 	}
 
 	if err := ar.usecase_user_dayoff.Create(&dayOff, userId); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(dayOffErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -244,6 +408,7 @@ func (ar *accountsRouter) ListDayOff(c *gin.Context) {
 	yearStr := c.Query("year")
 	weekStr := c.Query("week")
 	monthStr := c.Query("month")
+	timeZone := c.Query("tz") // Periods start at midnight in this zone, the user's by default
 
 	var year, week, month int
 
@@ -298,9 +463,9 @@ func (ar *accountsRouter) ListDayOff(c *gin.Context) {
 		}
 	}
 
-	dayOffs, err := ar.usecase_user_dayoff.GetAll(userId, filterType, year, week, month)
+	dayOffs, err := ar.usecase_user_dayoff.GetAll(userId, filterType, year, week, month, timeZone)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(dayOffErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -323,6 +488,10 @@ func (ar *accountsRouter) UpdateDayOff(c *gin.Context) {
 
 	// Mode param: all, single, future
 	mode := c.DefaultQuery("mode", "single")
+	occurrence, ok := occurrenceQuery(c)
+	if !ok {
+		return
+	}
 
 	var input UserDayOffInput
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -334,10 +503,12 @@ func (ar *accountsRouter) UpdateDayOff(c *gin.Context) {
 		ID:       &id,
 		InitHour: &input.InitHour,
 		EndHour:  &input.EndHour,
+		RRule:    input.RRule,
+		TimeZone: input.TimeZone,
 	}
 
-	if err := ar.usecase_user_dayoff.Update(&dayOff, userId, mode); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := ar.usecase_user_dayoff.Update(&dayOff, userId, mode, occurrence); err != nil {
+		c.JSON(dayOffErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -360,15 +531,116 @@ func (ar *accountsRouter) DeleteDayOff(c *gin.Context) {
 
 	// Mode param: all, single, future
 	mode := c.DefaultQuery("mode", "single")
+	occurrence, ok := occurrenceQuery(c)
+	if !ok {
+		return
+	}
 
-	if err := ar.usecase_user_dayoff.Delete(id, userId, mode); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := ar.usecase_user_dayoff.Delete(id, userId, mode, occurrence); err != nil {
+		c.JSON(dayOffErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Day off deleted successfully"})
 }
 
+func (ar *accountsRouter) ListDayOffExceptions(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	exceptions, err := ar.usecase_user_dayoff.GetExceptions(id, userId)
+	if err != nil {
+		c.JSON(dayOffErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, exceptions)
+}
+
+func (ar *accountsRouter) RestoreDayOffOccurrence(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	occurrence, ok := occurrenceQuery(c)
+	if !ok {
+		return
+	}
+	if occurrence == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "occurrence parameter is required"})
+		return
+	}
+
+	if err := ar.usecase_user_dayoff.RestoreOccurrence(id, userId, *occurrence); err != nil {
+		c.JSON(dayOffErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Occurrence restored successfully"})
+}
+
+func (ar *accountsRouter) ImportDayOff(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run parameter"})
+		return
+	}
+
+	// The calendar is sent as the "file" field of a form, or as the whole body
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCalendarImportSize)
+	var calendar io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		file, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer file.Close()
+		calendar = file
+	}
+
+	report, err := ar.usecase_user_dayoff.Import(calendar, userId, dryRun)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase_accounts.ErrInvalidCalendar):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 func MountAccountsRouter(router *gin.Engine, DB *gorm.DB, authMiddleware gin.HandlerFunc) *gin.Engine {
 	repoUser := repository_accounts.NewUserRepository(DB)
 	usecaseUser := usecase_accounts.NewUserUseCase(repoUser)
@@ -377,7 +649,12 @@ func MountAccountsRouter(router *gin.Engine, DB *gorm.DB, authMiddleware gin.Han
 	usecasePix := usecase_accounts.NewUserPixUseCase(repoPix)
 
 	repoDayOff := repository_accounts.NewUserDayOffRepository(DB)
-	usecaseDayOff := usecase_accounts.NewUserDayOffUseCase(repoDayOff)
+	transactionDayOff := func(fn func(repo usecase_accounts.IRepositoryUserDayOff) error) error {
+		return DB.Transaction(func(tx *gorm.DB) error {
+			return fn(repository_accounts.NewUserDayOffRepository(tx))
+		})
+	}
+	usecaseDayOff := usecase_accounts.NewUserDayOffUseCase(repoDayOff, transactionDayOff, repoUser)
 
 	// router group /auth
 	ar := NewAccountsRouter(usecaseUser, usecasePix, usecaseDayOff)
@@ -391,18 +668,25 @@ func MountAccountsRouter(router *gin.Engine, DB *gorm.DB, authMiddleware gin.Han
 	api.Use(authMiddleware)
 	{
 		api.GET("/user/profile", ar.GetMe)
+		api.PUT("/user/timezone", ar.SetTimeZone)
+		api.GET("/user/calendar", ar.GetCalendarToken)
+		api.POST("/user/calendar/reset", ar.ResetCalendarToken)
 
 		// Pix Routes
 		api.POST("/user/pix", ar.CreatePix)
 		api.GET("/user/pix", ar.ListPix)
 		api.GET("/user/pix/:id", ar.GetPix)
+		api.PUT("/user/pix/:id", ar.UpdatePix)
 		api.DELETE("/user/pix/:id", ar.DeletePix)
 
 		// DayOff Routes
 		api.POST("/user/dayoff", ar.CreateDayOff)
 		api.GET("/user/dayoff", ar.ListDayOff)
+		api.POST("/user/dayoff/import", ar.ImportDayOff)
 		api.PUT("/user/dayoff/:id", ar.UpdateDayOff)
 		api.DELETE("/user/dayoff/:id", ar.DeleteDayOff)
+		api.GET("/user/dayoff/:id/exceptions", ar.ListDayOffExceptions)
+		api.DELETE("/user/dayoff/:id/exceptions", ar.RestoreDayOffOccurrence)
 	}
 	return router
 }
@@ -2,6 +2,8 @@ package api
 
 import (
 	accounts_router "app/api/accounts"
+	crew_router "app/api/crew"
+	movies_router "app/api/movies"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -20,6 +22,8 @@ func SetupRouter(DB *gorm.DB) *gin.Engine {
 		})
 	})
 	r = accounts_router.MountAccountsRouter(r, DB, AuthMiddleware())
+	r = crew_router.MountCrewRouter(r, DB, AuthMiddleware())
+	r = movies_router.MountMoviesRouter(r, DB, AuthMiddleware())
 
 	protected := r.Group("/api")
 	protected.Use(AuthMiddleware())
@@ -0,0 +1,173 @@
+package crew_router
+
+import (
+	usecase_crew "app/usecase/crew"
+	"app/utils/token"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (cr *crewRouter) GetAvailability(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	from, ok := parseTimeQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to")
+	if !ok {
+		return
+	}
+	if from == nil || to == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Availability requires both 'from' and 'to' parameters"})
+		return
+	}
+
+	// 0 means every member must be available
+	minMembers := 0
+	if minMembersStr := c.Query("min_members"); minMembersStr != "" {
+		minMembers, err = strconv.Atoi(minMembersStr)
+		if err != nil || minMembers < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_members parameter (must be a positive integer)"})
+			return
+		}
+	}
+
+	availability, err := cr.usecase_crew_availability.GetAvailability(id, userId, *from, *to, minMembers)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (cr *crewRouter) SuggestSlots(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	from, ok := parseTimeQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to")
+	if !ok {
+		return
+	}
+	if from == nil || to == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Suggestions require both 'from' and 'to' parameters"})
+		return
+	}
+
+	durationMinutes, err := strconv.Atoi(c.Query("duration_minutes"))
+	if err != nil || durationMinutes <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration_minutes parameter (must be a positive integer)"})
+		return
+	}
+
+	options := usecase_crew.SuggestionOptions{
+		From:              *from,
+		To:                *to,
+		Duration:          time.Duration(durationMinutes) * time.Minute,
+		PreferredFromHour: usecase_crew.DefaultPreferredFromHour,
+		PreferredToHour:   usecase_crew.DefaultPreferredToHour,
+		Location:          time.UTC,
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		options.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || options.Limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be a positive integer)"})
+			return
+		}
+	}
+
+	if minMembersStr := c.Query("min_members"); minMembersStr != "" {
+		options.MinMembers, err = strconv.Atoi(minMembersStr)
+		if err != nil || options.MinMembers < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid min_members parameter (must be a positive integer)"})
+			return
+		}
+	}
+
+	// Preferred hours as "18-23"
+	if hoursStr := c.Query("preferred_hours"); hoursStr != "" {
+		parts := strings.Split(hoursStr, "-")
+		if len(parts) != 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid preferred_hours parameter (e.g. '18-23')"})
+			return
+		}
+		fromHour, errFrom := strconv.Atoi(parts[0])
+		toHour, errTo := strconv.Atoi(parts[1])
+		if errFrom != nil || errTo != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid preferred_hours parameter (e.g. '18-23')"})
+			return
+		}
+		options.PreferredFromHour = fromHour
+		options.PreferredToHour = toHour
+	}
+
+	// Preferred weekdays as "fri,sat,sun"
+	if weekdaysStr := c.Query("preferred_weekdays"); weekdaysStr != "" {
+		for _, name := range strings.Split(weekdaysStr, ",") {
+			weekday, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid preferred_weekdays parameter (e.g. 'fri,sat')"})
+				return
+			}
+			options.PreferredWeekdays = append(options.PreferredWeekdays, weekday)
+		}
+	}
+
+	if tz := c.Query("tz"); tz != "" {
+		options.Location, err = time.LoadLocation(tz)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tz parameter (must be an IANA time zone)"})
+			return
+		}
+	}
+
+	suggestions, err := cr.usecase_crew_availability.SuggestSlots(id, userId, options)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
@@ -0,0 +1,40 @@
+package crew_router
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// UserCalendar serves an iCalendar feed. Feeds are fetched by calendar apps,
+// which can not send the Authorization header, so they authenticate with the
+// user's calendar token in the query string.
+func (cr *crewRouter) UserCalendar(c *gin.Context) {
+	calendar, err := cr.usecase_crew_calendar.UserCalendar(c.Query("token"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, calendarContentType, calendar.Encode())
+}
+
+func (cr *crewRouter) CrewCalendar(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	calendar, err := cr.usecase_crew_calendar.CrewCalendar(id, c.Query("token"))
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, calendarContentType, calendar.Encode())
+}
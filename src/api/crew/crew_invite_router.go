@@ -0,0 +1,193 @@
+package crew_router
+
+import (
+	"app/utils/token"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CrewInviteInput struct {
+	Email          string `json:"email" binding:"omitempty,email"`
+	ExpiresInHours int    `json:"expires_in_hours" binding:"min=0"`
+	MaxUses        int    `json:"max_uses" binding:"min=0"`
+}
+
+type JoinCrewInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func (cr *crewRouter) CreateInvite(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input CrewInviteInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expiresIn := time.Duration(input.ExpiresInHours) * time.Hour
+
+	// With an email we invite that user directly, otherwise we issue a shareable code.
+	if input.Email != "" {
+		invite, err := cr.usecase_crew_invite.CreateEmailInvite(id, userId, input.Email, expiresIn)
+		if err != nil {
+			c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, invite)
+		return
+	}
+
+	invite, err := cr.usecase_crew_invite.CreateCodeInvite(id, userId, expiresIn, input.MaxUses)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, invite)
+}
+
+func (cr *crewRouter) ListInvites(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	invites, err := cr.usecase_crew_invite.GetAllByCrew(id, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+func (cr *crewRouter) RevokeInvite(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	inviteId, err := uuid.Parse(c.Param("invite_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := cr.usecase_crew_invite.Revoke(id, inviteId, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invite revoked successfully"})
+}
+
+func (cr *crewRouter) ListMyInvites(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invites, err := cr.usecase_crew_invite.GetPending(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, invites)
+}
+
+func (cr *crewRouter) AcceptInvite(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	inviteId, err := uuid.Parse(c.Param("invite_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	member, err := cr.usecase_crew_invite.Accept(inviteId, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (cr *crewRouter) DeclineInvite(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	inviteId, err := uuid.Parse(c.Param("invite_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := cr.usecase_crew_invite.Decline(inviteId, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invite declined successfully"})
+}
+
+func (cr *crewRouter) JoinCrew(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input JoinCrewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := cr.usecase_crew_invite.AcceptCode(input.Code, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
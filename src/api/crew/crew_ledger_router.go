@@ -0,0 +1,220 @@
+package crew_router
+
+import (
+	entity_crew "app/entity/crew"
+	"app/utils/token"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CrewPaymentInput struct {
+	ToUserID    int   `json:"to_user_id" binding:"required"`
+	AmountCents int64 `json:"amount_cents" binding:"required,gt=0"`
+}
+
+func (cr *crewRouter) GetBalances(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	balances, err := cr.usecase_crew_ledger.GetBalances(id, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, balances)
+}
+
+func (cr *crewRouter) SettleUp(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	transfers, err := cr.usecase_crew_ledger.SettleUp(id, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfers)
+}
+
+func (cr *crewRouter) getPixCharge(c *gin.Context) (*entity_crew.PixCharge, bool) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return nil, false
+	}
+
+	toUserId, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return nil, false
+	}
+
+	charge, err := cr.usecase_crew_ledger.GetPixCharge(id, userId, toUserId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return charge, true
+}
+
+func (cr *crewRouter) GetPixCharge(c *gin.Context) {
+	charge, ok := cr.getPixCharge(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, charge)
+}
+
+func (cr *crewRouter) GetPixQRCode(c *gin.Context) {
+	charge, ok := cr.getPixCharge(c)
+	if !ok {
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", charge.QRCodePNG)
+}
+
+func (cr *crewRouter) ListPayments(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	payments, err := cr.usecase_crew_ledger.GetPayments(id, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+func (cr *crewRouter) CreatePayment(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input CrewPaymentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment := entity_crew.CrewPayment{
+		ToUserID:    input.ToUserID,
+		AmountCents: input.AmountCents,
+	}
+
+	if err := cr.usecase_crew_ledger.RecordPayment(&payment, id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+func (cr *crewRouter) ConfirmPayment(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	paymentId, err := uuid.Parse(c.Param("payment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := cr.usecase_crew_ledger.ConfirmPayment(paymentId, id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment confirmed successfully"})
+}
+
+func (cr *crewRouter) RejectPayment(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	paymentId, err := uuid.Parse(c.Param("payment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := cr.usecase_crew_ledger.RejectPayment(paymentId, id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payment rejected successfully"})
+}
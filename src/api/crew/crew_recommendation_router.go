@@ -0,0 +1,52 @@
+package crew_router
+
+import (
+	"app/utils/token"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func (cr *crewRouter) GetRecommendations(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var sessionId *uuid.UUID
+	if sessionStr := c.Query("session_id"); sessionStr != "" {
+		parsed, err := uuid.Parse(sessionStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session_id parameter"})
+			return
+		}
+		sessionId = &parsed
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be a positive integer)"})
+			return
+		}
+	}
+
+	recommendations, err := cr.usecase_crew_recommendation.Recommend(id, userId, sessionId, limit)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendations)
+}
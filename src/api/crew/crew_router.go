@@ -0,0 +1,446 @@
+package crew_router
+
+import (
+	"app/conf"
+	entity_crew "app/entity/crew"
+	repository_accounts "app/infrascture/database/postgres/repository/accounts"
+	repository_crew "app/infrascture/database/postgres/repository/crew"
+	repository_movies "app/infrascture/database/postgres/repository/movies"
+	usecase_accounts "app/usecase/accounts"
+	usecase_crew "app/usecase/crew"
+	usecase_movies "app/usecase/movies"
+	"app/utils/token"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CrewInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CrewMemberInput struct {
+	UserID int    `json:"user_id" binding:"required"`
+	Role   string `json:"role"`
+}
+
+type CrewMemberRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type crewRouter struct {
+	usecase_crew                usecase_crew.IUseCaseCrew
+	usecase_crew_member         usecase_crew.IUseCaseCrewMember
+	usecase_crew_invite         usecase_crew.IUseCaseCrewInvite
+	usecase_movie_session       usecase_crew.IUseCaseMovieSession
+	usecase_crew_availability   usecase_crew.IUseCaseCrewAvailability
+	usecase_session_rsvp        usecase_crew.IUseCaseSessionRSVP
+	usecase_session_expense     usecase_crew.IUseCaseSessionExpense
+	usecase_crew_ledger         usecase_crew.IUseCaseCrewLedger
+	usecase_crew_watchlist      usecase_crew.IUseCaseCrewWatchlist
+	usecase_session_poll        usecase_crew.IUseCaseSessionPoll
+	usecase_crew_rating         usecase_crew.IUseCaseCrewRating
+	usecase_crew_recommendation usecase_crew.IUseCaseCrewRecommendation
+	usecase_crew_calendar       usecase_crew.IUseCaseCrewCalendar
+}
+
+func NewCrewRouter(usecase_crew usecase_crew.IUseCaseCrew, usecase_crew_member usecase_crew.IUseCaseCrewMember, usecase_crew_invite usecase_crew.IUseCaseCrewInvite, usecase_movie_session usecase_crew.IUseCaseMovieSession, usecase_crew_availability usecase_crew.IUseCaseCrewAvailability, usecase_session_rsvp usecase_crew.IUseCaseSessionRSVP, usecase_session_expense usecase_crew.IUseCaseSessionExpense, usecase_crew_ledger usecase_crew.IUseCaseCrewLedger, usecase_crew_watchlist usecase_crew.IUseCaseCrewWatchlist, usecase_session_poll usecase_crew.IUseCaseSessionPoll, usecase_crew_rating usecase_crew.IUseCaseCrewRating, usecase_crew_recommendation usecase_crew.IUseCaseCrewRecommendation, usecase_crew_calendar usecase_crew.IUseCaseCrewCalendar) *crewRouter {
+	return &crewRouter{
+		usecase_crew:                usecase_crew,
+		usecase_crew_member:         usecase_crew_member,
+		usecase_crew_invite:         usecase_crew_invite,
+		usecase_movie_session:       usecase_movie_session,
+		usecase_crew_availability:   usecase_crew_availability,
+		usecase_session_rsvp:        usecase_session_rsvp,
+		usecase_session_expense:     usecase_session_expense,
+		usecase_crew_ledger:         usecase_crew_ledger,
+		usecase_crew_watchlist:      usecase_crew_watchlist,
+		usecase_session_poll:        usecase_session_poll,
+		usecase_crew_rating:         usecase_crew_rating,
+		usecase_crew_recommendation: usecase_crew_recommendation,
+		usecase_crew_calendar:       usecase_crew_calendar,
+	}
+}
+
+// errorStatus maps crew use case errors to HTTP status codes, falling back
+// to the given status for anything else.
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, usecase_crew.ErrCrewNotFound),
+		errors.Is(err, usecase_crew.ErrInviteNotFound),
+		errors.Is(err, usecase_crew.ErrSessionNotFound),
+		errors.Is(err, usecase_crew.ErrExpenseNotFound),
+		errors.Is(err, usecase_crew.ErrPaymentNotFound),
+		errors.Is(err, usecase_crew.ErrWatchlistItemNotFound),
+		errors.Is(err, usecase_crew.ErrPollNotFound),
+		errors.Is(err, usecase_movies.ErrMovieNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase_crew.ErrCrewForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, usecase_crew.ErrSessionHasExpenses),
+		errors.Is(err, usecase_crew.ErrWatchlistItemInPoll):
+		return http.StatusConflict
+	case errors.Is(err, usecase_accounts.ErrInvalidCalendarToken):
+		return http.StatusUnauthorized
+	}
+	return fallback
+}
+
+func (cr *crewRouter) CreateCrew(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input CrewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	crew := entity_crew.Crew{
+		Name: input.Name,
+	}
+
+	if err := cr.usecase_crew.Create(&crew, userId); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, crew)
+}
+
+func (cr *crewRouter) ListCrew(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	crews, err := cr.usecase_crew.GetAll(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, crews)
+}
+
+func (cr *crewRouter) GetCrew(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	crew, err := cr.usecase_crew.GetById(id, userId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, crew)
+}
+
+func (cr *crewRouter) UpdateCrew(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input CrewInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	crew := entity_crew.Crew{
+		ID:   &id,
+		Name: input.Name,
+	}
+
+	if err := cr.usecase_crew.Update(&crew, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, crew)
+}
+
+func (cr *crewRouter) DeleteCrew(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := cr.usecase_crew.Delete(id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Crew deleted successfully"})
+}
+
+func (cr *crewRouter) AddMember(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input CrewMemberInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := cr.usecase_crew_member.Add(id, userId, input.UserID, input.Role)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+func (cr *crewRouter) ListMembers(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	members, err := cr.usecase_crew_member.GetAll(id, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+func (cr *crewRouter) UpdateMemberRole(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	memberUserId, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	var input CrewMemberRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := cr.usecase_crew_member.UpdateRole(id, userId, memberUserId, input.Role); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Crew member updated successfully"})
+}
+
+func (cr *crewRouter) RemoveMember(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	memberUserId, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	if err := cr.usecase_crew_member.Remove(id, userId, memberUserId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Crew member removed successfully"})
+}
+
+func MountCrewRouter(router *gin.Engine, DB *gorm.DB, authMiddleware gin.HandlerFunc) *gin.Engine {
+	repoUser := repository_accounts.NewUserRepository(DB)
+	repoDayOff := repository_accounts.NewUserDayOffRepository(DB)
+	repoPix := repository_accounts.NewUserPixRepository(DB)
+	repoMovie := repository_movies.NewMovieRepository(DB)
+	repoWatchEntry := repository_movies.NewWatchEntryRepository(DB)
+
+	repoCrew := repository_crew.NewCrewRepository(DB)
+	repoMember := repository_crew.NewCrewMemberRepository(DB)
+	repoInvite := repository_crew.NewCrewInviteRepository(DB)
+	repoSession := repository_crew.NewMovieSessionRepository(DB)
+	repoRSVP := repository_crew.NewSessionRSVPRepository(DB)
+	repoExpense := repository_crew.NewSessionExpenseRepository(DB)
+	repoPayment := repository_crew.NewCrewPaymentRepository(DB)
+	repoWatchlist := repository_crew.NewCrewWatchlistRepository(DB)
+	repoPoll := repository_crew.NewSessionPollRepository(DB)
+	transactionRSVP := func(fn func(repo usecase_crew.IRepositorySessionRSVP) error) error {
+		return DB.Transaction(func(tx *gorm.DB) error {
+			return fn(repository_crew.NewSessionRSVPRepository(tx))
+		})
+	}
+	usecaseCrew := usecase_crew.NewCrewUseCase(repoCrew, repoMember)
+	usecaseMember := usecase_crew.NewCrewMemberUseCase(repoCrew, repoMember, repoUser)
+	usecaseInvite := usecase_crew.NewCrewInviteUseCase(repoCrew, repoMember, repoInvite, repoUser)
+	usecaseSession := usecase_crew.NewMovieSessionUseCase(repoCrew, repoMember, repoSession, repoRSVP, transactionRSVP, repoWatchlist, repoMovie)
+	usecaseAvailability := usecase_crew.NewCrewAvailabilityUseCase(repoCrew, repoMember, repoDayOff)
+	usecaseRSVP := usecase_crew.NewSessionRSVPUseCase(repoCrew, repoMember, repoSession, repoRSVP, transactionRSVP)
+	usecaseExpense := usecase_crew.NewSessionExpenseUseCase(repoCrew, repoMember, repoSession, repoRSVP, repoExpense, repoPix)
+	usecaseLedger := usecase_crew.NewCrewLedgerUseCase(repoCrew, repoMember, repoExpense, repoPayment, repoUser, repoPix, conf.LoadConfig().PixMerchantCity)
+	usecaseWatchlist := usecase_crew.NewCrewWatchlistUseCase(repoCrew, repoMember, repoWatchlist, repoSession, repoMovie)
+	usecasePoll := usecase_crew.NewSessionPollUseCase(repoCrew, repoMember, repoSession, repoWatchlist, repoPoll)
+	usecaseHistory := usecase_movies.NewWatchHistoryUseCase(repoWatchEntry, repoMovie)
+	usecaseRating := usecase_crew.NewCrewRatingUseCase(repoCrew, repoMember, usecaseHistory)
+	usecaseRecommendation := usecase_crew.NewCrewRecommendationUseCase(repoCrew, repoMember, repoSession, repoRSVP, repoWatchEntry, repoMovie, usecaseHistory)
+	usecaseCalendar := usecase_crew.NewCrewCalendarUseCase(repoCrew, repoMember, repoSession, repoRSVP, repoUser, repoDayOff, conf.LoadConfig().CalendarDomain)
+
+	cr := NewCrewRouter(usecaseCrew, usecaseMember, usecaseInvite, usecaseSession, usecaseAvailability, usecaseRSVP, usecaseExpense, usecaseLedger, usecaseWatchlist, usecasePoll, usecaseRating, usecaseRecommendation, usecaseCalendar)
+	// router group /api
+	api := router.Group("/api")
+	api.Use(authMiddleware)
+	{
+		// Crew Routes
+		api.POST("/crew", cr.CreateCrew)
+		api.GET("/crew", cr.ListCrew)
+		api.GET("/crew/:id", cr.GetCrew)
+		api.PUT("/crew/:id", cr.UpdateCrew)
+		api.DELETE("/crew/:id", cr.DeleteCrew)
+
+		// Crew Member Routes
+		api.POST("/crew/:id/members", cr.AddMember)
+		api.GET("/crew/:id/members", cr.ListMembers)
+		api.PUT("/crew/:id/members/:user_id", cr.UpdateMemberRole)
+		api.DELETE("/crew/:id/members/:user_id", cr.RemoveMember)
+
+		// Crew Invite Routes
+		api.POST("/crew/:id/invites", cr.CreateInvite)
+		api.GET("/crew/:id/invites", cr.ListInvites)
+		api.DELETE("/crew/:id/invites/:invite_id", cr.RevokeInvite)
+		api.GET("/invites", cr.ListMyInvites)
+		api.POST("/invites/join", cr.JoinCrew)
+		api.POST("/invites/:invite_id/accept", cr.AcceptInvite)
+		api.POST("/invites/:invite_id/decline", cr.DeclineInvite)
+
+		// Movie Session Routes
+		api.POST("/crew/:id/sessions", cr.CreateSession)
+		api.GET("/crew/:id/sessions", cr.ListSessions)
+		api.GET("/crew/:id/sessions/:session_id", cr.GetSession)
+		api.PUT("/crew/:id/sessions/:session_id", cr.UpdateSession)
+		api.DELETE("/crew/:id/sessions/:session_id", cr.DeleteSession)
+
+		// RSVP Routes
+		api.PUT("/crew/:id/sessions/:session_id/rsvp", cr.RespondSession)
+		api.DELETE("/crew/:id/sessions/:session_id/rsvp", cr.WithdrawSession)
+		api.GET("/crew/:id/sessions/:session_id/rsvps", cr.GetSessionRSVPs)
+
+		// Poll Routes
+		api.POST("/crew/:id/sessions/:session_id/poll", cr.OpenPoll)
+		api.GET("/crew/:id/sessions/:session_id/poll", cr.GetPoll)
+		api.PUT("/crew/:id/sessions/:session_id/poll/ballot", cr.VotePoll)
+		api.POST("/crew/:id/sessions/:session_id/poll/close", cr.ClosePoll)
+
+		// Expense Routes
+		api.POST("/crew/:id/sessions/:session_id/expenses", cr.CreateExpense)
+		api.GET("/crew/:id/sessions/:session_id/expenses", cr.ListExpenses)
+		api.DELETE("/crew/:id/sessions/:session_id/expenses/:expense_id", cr.DeleteExpense)
+		api.GET("/crew/:id/sessions/:session_id/debts", cr.ListSessionDebts)
+
+		// Ledger Routes
+		api.GET("/crew/:id/balances", cr.GetBalances)
+		api.GET("/crew/:id/settle-up", cr.SettleUp)
+		api.GET("/crew/:id/settle-up/:user_id/pix", cr.GetPixCharge)
+		api.GET("/crew/:id/settle-up/:user_id/pix.png", cr.GetPixQRCode)
+		api.GET("/crew/:id/payments", cr.ListPayments)
+		api.POST("/crew/:id/payments", cr.CreatePayment)
+		api.POST("/crew/:id/payments/:payment_id/confirm", cr.ConfirmPayment)
+		api.POST("/crew/:id/payments/:payment_id/reject", cr.RejectPayment)
+
+		// Watchlist Routes
+		api.POST("/crew/:id/watchlist", cr.AddToWatchlist)
+		api.GET("/crew/:id/watchlist", cr.ListWatchlist)
+		api.PUT("/crew/:id/watchlist/:item_id/position", cr.MoveWatchlistItem)
+		api.POST("/crew/:id/watchlist/:item_id/watched", cr.MarkWatchlistItemWatched)
+		api.DELETE("/crew/:id/watchlist/:item_id", cr.RemoveFromWatchlist)
+
+		// Rating and Recommendation Routes
+		api.GET("/crew/:id/movies/:movie_id/ratings", cr.GetMovieRatings)
+		api.GET("/crew/:id/recommendations", cr.GetRecommendations)
+
+		// Availability Routes
+		api.GET("/crew/:id/availability", cr.GetAvailability)
+		api.GET("/crew/:id/suggestions", cr.SuggestSlots)
+	}
+
+	// Calendar feeds authenticate with the calendar token instead of the JWT
+	feeds := router.Group("/api")
+	{
+		feeds.GET("/user/calendar.ics", cr.UserCalendar)
+		feeds.GET("/crew/:id/calendar.ics", cr.CrewCalendar)
+	}
+	return router
+}
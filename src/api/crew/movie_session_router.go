@@ -0,0 +1,218 @@
+package crew_router
+
+import (
+	entity_crew "app/entity/crew"
+	"app/utils/token"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MovieSessionInput struct {
+	Title           string     `json:"title"` // Defaults to the movie title
+	MovieID         *uuid.UUID `json:"movie_id"`
+	WatchlistItemID *uuid.UUID `json:"watchlist_item_id"` // Only when creating
+	Description     string     `json:"description"`
+	StartAt         time.Time  `json:"start_at" binding:"required"`
+	EndAt           time.Time  `json:"end_at" binding:"required"`
+	LocationType    string     `json:"location_type" binding:"required"`
+	Location        string     `json:"location"`
+	StreamingURL    string     `json:"streaming_url"`
+	Capacity        int        `json:"capacity" binding:"min=0"`
+}
+
+// parseTimeQuery parses an optional RFC 3339 (or YYYY-MM-DD, as UTC midnight)
+// query parameter, writing a 400 response when it is malformed.
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(time.DateOnly, value)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid '" + name + "' parameter (must be RFC 3339 or YYYY-MM-DD)"})
+		return nil, false
+	}
+	return &t, true
+}
+
+func (cr *crewRouter) CreateSession(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input MovieSessionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session := entity_crew.MovieSession{
+		Title:        input.Title,
+		MovieID:      input.MovieID,
+		Description:  input.Description,
+		StartAt:      &input.StartAt,
+		EndAt:        &input.EndAt,
+		LocationType: input.LocationType,
+		Location:     input.Location,
+		StreamingURL: input.StreamingURL,
+		Capacity:     input.Capacity,
+	}
+
+	if err := cr.usecase_movie_session.Create(&session, input.WatchlistItemID, id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func (cr *crewRouter) ListSessions(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	from, ok := parseTimeQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	sessions, err := cr.usecase_movie_session.GetAll(id, userId, from, to)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+func (cr *crewRouter) GetSession(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	session, err := cr.usecase_movie_session.GetById(sessionId, id, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusNotFound), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (cr *crewRouter) UpdateSession(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input MovieSessionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session := entity_crew.MovieSession{
+		ID:           &sessionId,
+		Title:        input.Title,
+		MovieID:      input.MovieID,
+		Description:  input.Description,
+		StartAt:      &input.StartAt,
+		EndAt:        &input.EndAt,
+		LocationType: input.LocationType,
+		Location:     input.Location,
+		StreamingURL: input.StreamingURL,
+		Capacity:     input.Capacity,
+	}
+
+	if err := cr.usecase_movie_session.Update(&session, id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+func (cr *crewRouter) DeleteSession(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := cr.usecase_movie_session.Delete(sessionId, id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Movie session deleted successfully"})
+}
@@ -0,0 +1,165 @@
+package crew_router
+
+import (
+	entity_crew "app/entity/crew"
+	usecase_crew "app/usecase/crew"
+	"app/utils/token"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ExpenseParticipantInput struct {
+	UserID      int   `json:"user_id" binding:"required"`
+	Shares      int   `json:"shares"`
+	AmountCents int64 `json:"amount_cents"`
+}
+
+type SessionExpenseInput struct {
+	Description  string                    `json:"description" binding:"required"`
+	AmountCents  int64                     `json:"amount_cents" binding:"required,gt=0"`
+	SplitMode    string                    `json:"split_mode"`
+	Participants []ExpenseParticipantInput `json:"participants" binding:"dive"`
+}
+
+func (cr *crewRouter) CreateExpense(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input SessionExpenseInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expense := entity_crew.SessionExpense{
+		Description: input.Description,
+		AmountCents: input.AmountCents,
+		SplitMode:   input.SplitMode,
+	}
+
+	participants := []usecase_crew.ExpenseParticipant{}
+	for _, p := range input.Participants {
+		participants = append(participants, usecase_crew.ExpenseParticipant{
+			UserID:      p.UserID,
+			Shares:      p.Shares,
+			AmountCents: p.AmountCents,
+		})
+	}
+
+	if err := cr.usecase_session_expense.Create(&expense, participants, id, sessionId, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, expense)
+}
+
+func (cr *crewRouter) ListExpenses(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	expenses, err := cr.usecase_session_expense.GetAll(id, sessionId, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, expenses)
+}
+
+func (cr *crewRouter) DeleteExpense(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	expenseId, err := uuid.Parse(c.Param("expense_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := cr.usecase_session_expense.Delete(expenseId, id, sessionId, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Expense deleted successfully"})
+}
+
+func (cr *crewRouter) ListSessionDebts(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	debts, err := cr.usecase_session_expense.GetDebts(id, sessionId, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, debts)
+}
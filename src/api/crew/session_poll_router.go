@@ -0,0 +1,131 @@
+package crew_router
+
+import (
+	"app/utils/token"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SessionPollInput struct {
+	WatchlistItemIDs []uuid.UUID `json:"watchlist_item_ids" binding:"required"`
+	Deadline         time.Time   `json:"deadline" binding:"required"`
+}
+
+type PollBallotInput struct {
+	Ranking []uuid.UUID `json:"ranking" binding:"required"`
+}
+
+// parseSessionParams parses the crew and session IDs of the URL, writing a
+// 400 response when either is malformed.
+func parseSessionParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	sessionId, err := uuid.Parse(c.Param("session_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, sessionId, true
+}
+
+func (cr *crewRouter) OpenPoll(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, sessionId, ok := parseSessionParams(c)
+	if !ok {
+		return
+	}
+
+	var input SessionPollInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cr.usecase_session_poll.Open(id, sessionId, userId, input.WatchlistItemIDs, input.Deadline)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (cr *crewRouter) GetPoll(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, sessionId, ok := parseSessionParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := cr.usecase_session_poll.GetResult(id, sessionId, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (cr *crewRouter) VotePoll(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, sessionId, ok := parseSessionParams(c)
+	if !ok {
+		return
+	}
+
+	var input PollBallotInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := cr.usecase_session_poll.Vote(id, sessionId, userId, input.Ranking)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (cr *crewRouter) ClosePoll(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, sessionId, ok := parseSessionParams(c)
+	if !ok {
+		return
+	}
+
+	result, err := cr.usecase_session_poll.Close(id, sessionId, userId)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,126 @@
+package movies_router
+
+import (
+	entity_movies "app/entity/movies"
+	repository_movies "app/infrascture/database/postgres/repository/movies"
+	usecase_movies "app/usecase/movies"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type moviesRouter struct {
+	usecase_movie         usecase_movies.IUseCaseMovie
+	usecase_watch_history usecase_movies.IUseCaseWatchHistory
+}
+
+func NewMoviesRouter(usecase_movie usecase_movies.IUseCaseMovie, usecase_watch_history usecase_movies.IUseCaseWatchHistory) *moviesRouter {
+	return &moviesRouter{
+		usecase_movie:         usecase_movie,
+		usecase_watch_history: usecase_watch_history,
+	}
+}
+
+// parseIntQuery parses an optional integer query parameter, writing a 400
+// response when it is malformed.
+func parseIntQuery(c *gin.Context, name string) (*int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid '" + name + "' parameter"})
+		return nil, false
+	}
+	return &n, true
+}
+
+func (mr *moviesRouter) SearchMovies(c *gin.Context) {
+	filter := entity_movies.MovieFilter{
+		Query: c.Query("q"),
+		Genre: c.Query("genre"),
+	}
+
+	params := map[string]**int{
+		"year":        &filter.Year,
+		"year_from":   &filter.YearFrom,
+		"year_to":     &filter.YearTo,
+		"runtime_min": &filter.RuntimeMin,
+		"runtime_max": &filter.RuntimeMax,
+	}
+	for name, target := range params {
+		value, ok := parseIntQuery(c, name)
+		if !ok {
+			return
+		}
+		*target = value
+	}
+
+	page, ok := parseIntQuery(c, "page")
+	if !ok {
+		return
+	}
+	if page != nil {
+		filter.Page = *page
+	}
+	pageSize, ok := parseIntQuery(c, "page_size")
+	if !ok {
+		return
+	}
+	if pageSize != nil {
+		filter.PageSize = *pageSize
+	}
+
+	result, err := mr.usecase_movie.Search(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (mr *moviesRouter) GetMovie(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	movie, err := mr.usecase_movie.GetById(id)
+	if err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, movie)
+}
+
+func MountMoviesRouter(router *gin.Engine, DB *gorm.DB, authMiddleware gin.HandlerFunc) *gin.Engine {
+	repoMovie := repository_movies.NewMovieRepository(DB)
+	repoWatchEntry := repository_movies.NewWatchEntryRepository(DB)
+	usecaseMovie := usecase_movies.NewMovieUseCase(repoMovie)
+	usecaseWatchHistory := usecase_movies.NewWatchHistoryUseCase(repoWatchEntry, repoMovie)
+
+	mr := NewMoviesRouter(usecaseMovie, usecaseWatchHistory)
+	// router group /api
+	api := router.Group("/api")
+	api.Use(authMiddleware)
+	{
+		// Movie Routes
+		api.GET("/movies", mr.SearchMovies)
+		api.GET("/movies/:id", mr.GetMovie)
+
+		// Watch History Routes
+		api.POST("/user/history", mr.AddWatchEntry)
+		api.GET("/user/history", mr.ListWatchHistory)
+		api.PUT("/user/history/:id", mr.UpdateWatchEntry)
+		api.DELETE("/user/history/:id", mr.DeleteWatchEntry)
+	}
+	return router
+}
@@ -0,0 +1,136 @@
+package movies_router
+
+import (
+	entity_movies "app/entity/movies"
+	usecase_movies "app/usecase/movies"
+	"app/utils/token"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WatchEntryInput struct {
+	MovieID   uuid.UUID  `json:"movie_id" binding:"required"`
+	WatchedAt *time.Time `json:"watched_at"`
+	Rating    *float64   `json:"rating"`
+	Review    string     `json:"review"`
+}
+
+type WatchEntryUpdateInput struct {
+	WatchedAt *time.Time `json:"watched_at"`
+	Rating    *float64   `json:"rating"`
+	Review    string     `json:"review"`
+}
+
+// errorStatus maps movie use case errors to HTTP status codes, falling back
+// to the given status for anything else.
+func errorStatus(err error, fallback int) int {
+	if errors.Is(err, usecase_movies.ErrMovieNotFound) || errors.Is(err, usecase_movies.ErrWatchEntryNotFound) {
+		return http.StatusNotFound
+	}
+	return fallback
+}
+
+func (mr *moviesRouter) AddWatchEntry(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var input WatchEntryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := entity_movies.WatchEntry{
+		MovieID:   &input.MovieID,
+		WatchedAt: input.WatchedAt,
+		Rating:    input.Rating,
+		Review:    input.Review,
+	}
+
+	if err := mr.usecase_watch_history.Add(&entry, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+func (mr *moviesRouter) ListWatchHistory(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	entries, err := mr.usecase_watch_history.GetAll(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+func (mr *moviesRouter) UpdateWatchEntry(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	var input WatchEntryUpdateInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry := entity_movies.WatchEntry{
+		ID:        &id,
+		WatchedAt: input.WatchedAt,
+		Rating:    input.Rating,
+		Review:    input.Review,
+	}
+
+	if err := mr.usecase_watch_history.Update(&entry, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusBadRequest), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func (mr *moviesRouter) DeleteWatchEntry(c *gin.Context) {
+	userId, err := token.ExtractTokenID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid UUID"})
+		return
+	}
+
+	if err := mr.usecase_watch_history.Delete(id, userId); err != nil {
+		c.JSON(errorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Watch history entry deleted successfully"})
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	database_postgres "app/infrascture/database/postgres"
+	repository_movies "app/infrascture/database/postgres/repository/movies"
+	usecase_movies "app/usecase/movies"
+	"compress/gzip"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// Imports the movie catalog from dump files on disk, e.g.
+//
+//	go run ./cmd/import_movies -imdb title.basics.tsv.gz -tmdb movie_ids_10_18_2026.json.gz
+//
+// The IMDb file is imported first, so TMDB records can attach to the movies
+// it created through their imdb_id. Files ending in .gz are decompressed.
+func main() {
+	tmdbPath := flag.String("tmdb", "", "TMDB JSONL file, one movie per line")
+	imdbPath := flag.String("imdb", "", "IMDb title.basics.tsv file")
+	flag.Parse()
+
+	if *tmdbPath == "" && *imdbPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db := database_postgres.ConnectDB()
+	database_postgres.RunMigrations(db)
+	usecase := usecase_movies.NewMovieImportUseCase(repository_movies.NewMovieRepository(db))
+
+	if *imdbPath != "" {
+		importFile(*imdbPath, usecase.ImportIMDb)
+	}
+	if *tmdbPath != "" {
+		importFile(*tmdbPath, usecase.ImportTMDB)
+	}
+}
+
+func importFile(path string, importer func(r io.Reader) (*usecase_movies.ImportResult, error)) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Could not open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			log.Fatalf("Could not decompress %s: %v", path, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	result, err := importer(reader)
+	if result != nil {
+		log.Printf("%s: %d read, %d skipped, %d created, %d updated", path, result.Read, result.Skipped, result.Created, result.Updated)
+	}
+	if err != nil {
+		log.Fatalf("Import of %s failed: %v", path, err)
+	}
+}
@@ -11,6 +11,11 @@ type Config struct {
 	DBPort     string
 	DBName     string
 	APISecret  string
+	// City written in Pix BR Codes; users have no address, so it is global.
+	PixMerchantCity string
+	// Domain of the UIDs of iCalendar events; keep it stable or subscribed
+	// calendars will duplicate every event.
+	CalendarDomain string
 }
 
 func LoadConfig() *Config {
@@ -26,6 +31,9 @@ func LoadConfig() *Config {
 		DBPort:     os.Getenv("POSTGRES_PORT"),
 		DBName:     os.Getenv("POSTGRES_DB"),
 		APISecret:  apiSecret,
+
+		PixMerchantCity: getEnv("PIX_MERCHANT_CITY", "SAO PAULO"),
+		CalendarDomain:  getEnv("CALENDAR_DOMAIN", "movie-friends"),
 	}
 }
 
@@ -16,10 +16,14 @@ type User struct {
 	ID        int       `gorm:"primarykey" json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
-	Password  string    `json:"password"`
+	Password  string    `json:"-"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Role      string    `json:"role"`
+	// IANA time zone day offs are listed in and created in by default
+	TimeZone string `json:"time_zone" gorm:"default:'UTC'"`
+	// Secret of the user's iCalendar feeds, nil until first requested
+	CalendarToken *string `json:"-" gorm:"uniqueIndex"`
 }
 
 func (User) TableName() string {
@@ -21,10 +21,16 @@ type UserDayOff struct {
 	Owner          *User       `json:"owner"`
 	OwnerID        int         `json:"owner_id"` // Explicit FK for easier queries
 	Repeat         bool        `json:"repeat"`
-	RepeatType     string      `json:"repeat_type"`
-	RepeatValue    string      `json:"repeat_value"`
-	DayOffFatherID *uuid.UUID  `json:"day_off_father_id"`
+	RRule          string      `json:"rrule" gorm:"column:rrule;default:''"`      // RFC 5545 recurrence of the series, e.g. FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH
+	RepeatType     string      `json:"repeat_type"`                               // Frequency of RRule, kept for older clients
+	RepeatValue    string      `json:"repeat_value"`                              // Repetitions after the first day off, kept for older clients
+	ExDates        []time.Time `json:"exdates" gorm:"type:jsonb;serializer:json"` // Cancelled occurrences of the series
+	RecurrenceEnd  *time.Time  `json:"-"`                                         // End of the last occurrence of the series, nil when it never ends
+	TimeZone       string      `json:"time_zone" gorm:"default:'UTC'"`            // IANA time zone the series repeats in, keeping its wall-clock time
+	DayOffFatherID *uuid.UUID  `json:"day_off_father_id"`                         // Series this day off overrides an occurrence of
 	DayOffFather   *UserDayOff `json:"day_off_father" gorm:"foreignKey:DayOffFatherID"`
+	RecurrenceID   *time.Time  `json:"recurrence_id"`                         // Start of the occurrence of the series, as the rule generates it
+	ICalUID        *string     `json:"ical_uid" gorm:"column:ical_uid;index"` // UID of the imported VEVENT, with the RECURRENCE-ID for moved occurrences
 	CreatedAt      time.Time   `json:"created_at"`
 	UpdatedAt      time.Time   `json:"updated_at"`
 }
@@ -41,3 +47,12 @@ func (c *UserDayOff) BeforeCreate(tx *gorm.DB) (err error) {
 
 	return nil
 }
+
+// DayOffExceptions are the occurrences of a series that do not follow its
+// rule: the cancelled ones, by their start, and the overrides of the ones
+// that were changed.
+type DayOffExceptions struct {
+	SeriesID  *uuid.UUID    `json:"series_id"`
+	ExDates   []time.Time   `json:"exdates"`
+	Overrides []*UserDayOff `json:"overrides"`
+}
@@ -0,0 +1,31 @@
+package entity_accounts
+
+import "time"
+
+const (
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusError   = "error"
+)
+
+// DayOffImportEvent is the outcome of one VEVENT of an imported calendar.
+// On a dry run, created means it would be created.
+type DayOffImportEvent struct {
+	Index       int        `json:"index"`
+	UID         string     `json:"uid"`
+	Summary     string     `json:"summary"`
+	Status      string     `json:"status"`
+	Reason      string     `json:"reason,omitempty"`
+	InitHour    *time.Time `json:"init_hour,omitempty"`
+	EndHour     *time.Time `json:"end_hour,omitempty"`
+	RRule       string     `json:"rrule,omitempty"`
+	Occurrences int        `json:"occurrences"` // 0 for series that never end
+}
+
+type DayOffImportReport struct {
+	DryRun  bool                 `json:"dry_run"`
+	Created int                  `json:"created"`
+	Skipped int                  `json:"skipped"`
+	Errors  int                  `json:"errors"`
+	Events  []*DayOffImportEvent `json:"events"`
+}
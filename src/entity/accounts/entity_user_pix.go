@@ -11,7 +11,9 @@ type UserPix struct {
 	ID        *uuid.UUID `json:"id"`
 	Owner     *User      `json:"owner"`
 	OwnerID   int        `json:"owner_id"`
-	PixKey    string     `json:"pix_key"`
+	PixKey    string     `json:"pix_key"`    // Unique per owner, indexed by MigrateUserPixKeys
+	KeyType   string     `json:"key_type"`   // cpf, cnpj, email, phone or evp
+	IsPrimary bool       `json:"is_primary"` // The key payments are received on, one per owner
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 }
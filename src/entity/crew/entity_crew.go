@@ -12,6 +12,7 @@ type Crew struct {
 	ID        *uuid.UUID            `json:"id"`
 	Name      string                `json:"name"`
 	Owner     *entity_accounts.User `json:"owner"`
+	OwnerID   int                   `json:"owner_id"`
 	CreatedAt time.Time             `json:"created_at"`
 	UpdatedAt time.Time             `json:"updated_at"`
 }
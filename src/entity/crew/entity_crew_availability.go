@@ -0,0 +1,26 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AvailabilitySlot is a computed (not persisted) time window in which the
+// listed crew members are all on a day off.
+type AvailabilitySlot struct {
+	StartAt          time.Time               `json:"start_at"`
+	EndAt            time.Time               `json:"end_at"`
+	AvailableCount   int                     `json:"available_count"`
+	AvailableMembers []*entity_accounts.User `json:"available_members"`
+}
+
+type CrewAvailability struct {
+	CrewID       uuid.UUID           `json:"crew_id"`
+	From         time.Time           `json:"from"`
+	To           time.Time           `json:"to"`
+	MinMembers   int                 `json:"min_members"`
+	TotalMembers int                 `json:"total_members"`
+	Slots        []*AvailabilitySlot `json:"slots"`
+}
@@ -0,0 +1,58 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	InviteStatusPending  = "pending"
+	InviteStatusAccepted = "accepted"
+	InviteStatusDeclined = "declined"
+	InviteStatusExpired  = "expired"
+	InviteStatusRevoked  = "revoked"
+)
+
+// CrewInvite is either a shareable code invite (Code set, no invitee) or a
+// direct invite to an existing user (InviteeID set, no code).
+type CrewInvite struct {
+	ID          *uuid.UUID            `json:"id"`
+	Crew        *Crew                 `json:"crew,omitempty"`
+	CrewID      *uuid.UUID            `json:"crew_id" gorm:"index"`
+	Code        *string               `json:"code,omitempty" gorm:"uniqueIndex"`
+	Invitee     *entity_accounts.User `json:"invitee,omitempty"`
+	InviteeID   *int                  `json:"invitee_id" gorm:"index"`
+	IssuedBy    *entity_accounts.User `json:"issued_by,omitempty"`
+	IssuedByID  int                   `json:"issued_by_id"`
+	Status      string                `json:"status"`
+	MaxUses     int                   `json:"max_uses"` // 0 means unlimited, only used by code invites
+	Uses        int                   `json:"uses"`
+	ExpiresAt   *time.Time            `json:"expires_at"`
+	RespondedAt *time.Time            `json:"responded_at"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+func (c *CrewInvite) TableName() string {
+	return "crew_invites"
+}
+
+func (c *CrewInvite) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *CrewInvite) IsCodeInvite() bool {
+	return c.Code != nil
+}
+
+// IsExpired reports whether a pending invite has passed its expiry date.
+func (c *CrewInvite) IsExpired(now time.Time) bool {
+	return c.Status == InviteStatusPending && c.ExpiresAt != nil && !now.Before(*c.ExpiresAt)
+}
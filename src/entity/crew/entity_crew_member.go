@@ -0,0 +1,52 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	CREW_ROLE_OWNER  = "owner"
+	CREW_ROLE_ADMIN  = "admin"
+	CREW_ROLE_MEMBER = "member"
+)
+
+type CrewMember struct {
+	ID        *uuid.UUID            `json:"id"`
+	Crew      *Crew                 `json:"crew,omitempty"`
+	CrewID    *uuid.UUID            `json:"crew_id" gorm:"uniqueIndex:idx_crew_member"`
+	User      *entity_accounts.User `json:"user"`
+	UserID    int                   `json:"user_id" gorm:"uniqueIndex:idx_crew_member"`
+	Role      string                `json:"role"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func (c *CrewMember) TableName() string {
+	return "crew_members"
+}
+
+func (c *CrewMember) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// CanManageMembers reports whether the member may add, remove or change
+// the role of other members of the crew.
+func (c *CrewMember) CanManageMembers() bool {
+	return c.Role == CREW_ROLE_OWNER || c.Role == CREW_ROLE_ADMIN
+}
+
+func IsValidCrewRole(role string) bool {
+	switch role {
+	case CREW_ROLE_OWNER, CREW_ROLE_ADMIN, CREW_ROLE_MEMBER:
+		return true
+	}
+	return false
+}
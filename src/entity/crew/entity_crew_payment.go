@@ -0,0 +1,68 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PaymentStatusPaid      = "paid"      // Marked as paid by the debtor
+	PaymentStatusConfirmed = "confirmed" // Confirmed by the creditor, counts towards balances
+	PaymentStatusRejected  = "rejected"  // The creditor did not receive it
+)
+
+// CrewPayment is a transfer between two members that settles debts.
+type CrewPayment struct {
+	ID          *uuid.UUID            `json:"id"`
+	CrewID      *uuid.UUID            `json:"crew_id" gorm:"index"`
+	FromUser    *entity_accounts.User `json:"from_user,omitempty"`
+	FromUserID  int                   `json:"from_user_id"`
+	ToUser      *entity_accounts.User `json:"to_user,omitempty"`
+	ToUserID    int                   `json:"to_user_id"`
+	AmountCents int64                 `json:"amount_cents"`
+	Status      string                `json:"status"`
+	ConfirmedAt *time.Time            `json:"confirmed_at"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+func (c *CrewPayment) TableName() string {
+	return "crew_payments"
+}
+
+func (c *CrewPayment) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// CrewBalance is a member's computed net position in a crew: positive means
+// the crew owes them, negative means they owe the crew.
+type CrewBalance struct {
+	User         *entity_accounts.User `json:"user"`
+	BalanceCents int64                 `json:"balance_cents"`
+}
+
+// SettlementTransfer is one transfer of a computed settle-up plan.
+type SettlementTransfer struct {
+	From        *entity_accounts.User `json:"from"`
+	To          *entity_accounts.User `json:"to"`
+	AmountCents int64                 `json:"amount_cents"`
+	PixKey      *string               `json:"pix_key"`
+}
+
+// PixCharge is a ready-to-pay Pix BR Code for one settle-up transfer.
+type PixCharge struct {
+	From        *entity_accounts.User `json:"from"`
+	To          *entity_accounts.User `json:"to"`
+	AmountCents int64                 `json:"amount_cents"`
+	PixKey      string                `json:"pix_key"`
+	TxID        string                `json:"txid"` // Identifies the transfer when the payee reconciles it
+	Payload     string                `json:"payload"`
+	QRCodePNG   []byte                `json:"qr_code_png"`
+}
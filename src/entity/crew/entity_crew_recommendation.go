@@ -0,0 +1,10 @@
+package entity_crew
+
+import entity_movies "app/entity/movies"
+
+// MovieRecommendation is a catalog movie suggested to a crew.
+type MovieRecommendation struct {
+	Movie  *entity_movies.Movie `json:"movie"`
+	Score  float64              `json:"score"`  // Predicted crew rating, from 0.5 to 5 stars
+	Reason string               `json:"reason"` // Why it was recommended, e.g. "Matches the crew's taste for Drama"
+}
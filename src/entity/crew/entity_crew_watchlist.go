@@ -0,0 +1,39 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_movies "app/entity/movies"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CrewWatchlistItem is a catalog movie a crew wants to watch. Pending items
+// are ordered by Position; an item is watched once the session it was
+// picked for ends, or when a member marks it.
+type CrewWatchlistItem struct {
+	ID        *uuid.UUID            `json:"id"`
+	CrewID    *uuid.UUID            `json:"crew_id" gorm:"uniqueIndex:idx_crew_watchlist_movie"`
+	Movie     *entity_movies.Movie  `json:"movie,omitempty"`
+	MovieID   *uuid.UUID            `json:"movie_id" gorm:"uniqueIndex:idx_crew_watchlist_movie"`
+	AddedBy   *entity_accounts.User `json:"added_by,omitempty"`
+	AddedByID int                   `json:"added_by_id"`
+	Position  int                   `json:"position"`
+	SessionID *uuid.UUID            `json:"session_id" gorm:"index"` // Session the movie was picked for
+	WatchedAt *time.Time            `json:"watched_at"`
+	CreatedAt time.Time             `json:"created_at"` // When it was added
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func (c *CrewWatchlistItem) TableName() string {
+	return "crew_watchlist_items"
+}
+
+func (c *CrewWatchlistItem) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,47 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_movies "app/entity/movies"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	LocationTypeInPerson  = "in_person"
+	LocationTypeStreaming = "streaming"
+)
+
+type MovieSession struct {
+	ID           *uuid.UUID            `json:"id"`
+	Crew         *Crew                 `json:"crew,omitempty"`
+	CrewID       *uuid.UUID            `json:"crew_id" gorm:"index"`
+	Title        string                `json:"title"`
+	Movie        *entity_movies.Movie  `json:"movie,omitempty"`
+	MovieID      *uuid.UUID            `json:"movie_id"` // Catalog movie, if one was picked
+	Description  string                `json:"description"`
+	StartAt      *time.Time            `json:"start_at"`
+	EndAt        *time.Time            `json:"end_at"`
+	LocationType string                `json:"location_type"`
+	Location     string                `json:"location"`      // Address, used by in person sessions
+	StreamingURL string                `json:"streaming_url"` // Watch party link, used by streaming sessions
+	Capacity     int                   `json:"capacity"`      // Maximum attendees, 0 means unlimited
+	Organizer    *entity_accounts.User `json:"organizer,omitempty"`
+	OrganizerID  int                   `json:"organizer_id"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+func (c *MovieSession) TableName() string {
+	return "crew_movie_sessions"
+}
+
+func (c *MovieSession) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,80 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SplitModeEqual  = "equal"
+	SplitModeShares = "shares"
+	SplitModeExact  = "exact"
+)
+
+// SessionExpense is something a member paid for on behalf of others, such as
+// tickets or snacks. Amounts are stored in cents.
+type SessionExpense struct {
+	ID          *uuid.UUID            `json:"id"`
+	CrewID      *uuid.UUID            `json:"crew_id" gorm:"index"`
+	Session     *MovieSession         `json:"session,omitempty"`
+	SessionID   *uuid.UUID            `json:"session_id" gorm:"index"`
+	Payer       *entity_accounts.User `json:"payer,omitempty"`
+	PayerID     int                   `json:"payer_id"`
+	Description string                `json:"description"`
+	AmountCents int64                 `json:"amount_cents"`
+	SplitMode   string                `json:"split_mode"`
+	Shares      []*ExpenseShare       `json:"shares" gorm:"foreignKey:ExpenseID"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+func (c *SessionExpense) TableName() string {
+	return "crew_session_expenses"
+}
+
+func (c *SessionExpense) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// ExpenseShare is the part of an expense owed by one participant. The
+// payer's own share is recorded too but is never a debt.
+type ExpenseShare struct {
+	ID          *uuid.UUID            `json:"id"`
+	Expense     *SessionExpense       `json:"expense,omitempty"`
+	ExpenseID   *uuid.UUID            `json:"expense_id" gorm:"index"`
+	Debtor      *entity_accounts.User `json:"debtor,omitempty"`
+	DebtorID    int                   `json:"debtor_id" gorm:"index"`
+	Shares      int                   `json:"shares"` // Weight used by the shares split mode
+	AmountCents int64                 `json:"amount_cents"`
+	CreatedAt   time.Time             `json:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at"`
+}
+
+func (c *ExpenseShare) TableName() string {
+	return "crew_expense_shares"
+}
+
+func (c *ExpenseShare) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// ExpenseDebt is a computed view of a share owed to another member, with the
+// creditor's Pix key to pay it.
+type ExpenseDebt struct {
+	ExpenseID   *uuid.UUID            `json:"expense_id"`
+	Description string                `json:"description"`
+	Creditor    *entity_accounts.User `json:"creditor"`
+	AmountCents int64                 `json:"amount_cents"`
+	PixKey      *string               `json:"pix_key"`
+}
@@ -0,0 +1,119 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PollStatusOpen   = "open"
+	PollStatusClosed = "closed"
+)
+
+// SessionPoll is a ranked choice vote on the movie of a session, among
+// movies of the crew watchlist.
+type SessionPoll struct {
+	ID           *uuid.UUID            `json:"id"`
+	CrewID       *uuid.UUID            `json:"crew_id" gorm:"index"`
+	SessionID    *uuid.UUID            `json:"session_id" gorm:"uniqueIndex"`
+	CreatedBy    *entity_accounts.User `json:"created_by,omitempty"`
+	CreatedByID  int                   `json:"created_by_id"`
+	Candidates   []*PollCandidate      `json:"candidates" gorm:"foreignKey:PollID"`
+	Deadline     *time.Time            `json:"deadline"`
+	ClosedAt     *time.Time            `json:"closed_at"`      // Set at the deadline, when everyone voted or when closed by hand
+	WinnerItemID *uuid.UUID            `json:"winner_item_id"` // Watchlist item that won, nil without votes
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+func (c *SessionPoll) TableName() string {
+	return "crew_session_polls"
+}
+
+func (c *SessionPoll) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+func (c *SessionPoll) Status() string {
+	if c.ClosedAt != nil {
+		return PollStatusClosed
+	}
+	return PollStatusOpen
+}
+
+// PollCandidate is a watchlist movie that can be voted for in a poll.
+type PollCandidate struct {
+	ID              *uuid.UUID         `json:"id"`
+	PollID          *uuid.UUID         `json:"poll_id" gorm:"uniqueIndex:idx_poll_candidate"`
+	WatchlistItem   *CrewWatchlistItem `json:"watchlist_item,omitempty"`
+	WatchlistItemID *uuid.UUID         `json:"watchlist_item_id" gorm:"uniqueIndex:idx_poll_candidate"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+func (c *PollCandidate) TableName() string {
+	return "crew_poll_candidates"
+}
+
+func (c *PollCandidate) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	return nil
+}
+
+// PollBallot is the ranking of one voter, most preferred watchlist item
+// first. Unranked candidates are not voted for.
+type PollBallot struct {
+	ID        *uuid.UUID  `json:"id"`
+	PollID    *uuid.UUID  `json:"poll_id" gorm:"uniqueIndex:idx_poll_ballot"`
+	VoterID   int         `json:"voter_id" gorm:"uniqueIndex:idx_poll_ballot"`
+	Ranking   []uuid.UUID `json:"ranking" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func (c *PollBallot) TableName() string {
+	return "crew_poll_ballots"
+}
+
+func (c *PollBallot) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// PollTally is the number of ballots counting for a candidate in a round.
+type PollTally struct {
+	WatchlistItemID uuid.UUID `json:"watchlist_item_id"`
+	Votes           int       `json:"votes"`
+}
+
+// PollRound is one round of an instant-runoff count.
+type PollRound struct {
+	Round      int          `json:"round"`
+	Tallies    []*PollTally `json:"tallies"`    // Candidates still running
+	Exhausted  int          `json:"exhausted"`  // Ballots with no candidate left
+	Eliminated *uuid.UUID   `json:"eliminated"` // Watchlist item eliminated at the end of the round
+	Winner     *uuid.UUID   `json:"winner"`     // Set on the last round
+}
+
+// SessionPollResult is the computed state of a poll. Rounds and the winner
+// are only revealed once the poll is closed.
+type SessionPollResult struct {
+	Poll           *SessionPoll       `json:"poll"`
+	Status         string             `json:"status"`
+	EligibleVoters int                `json:"eligible_voters"`
+	Votes          int                `json:"votes"`
+	MyRanking      []uuid.UUID        `json:"my_ranking"`
+	Rounds         []*PollRound       `json:"rounds"`
+	Winner         *CrewWatchlistItem `json:"winner"`
+}
@@ -0,0 +1,55 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	RSVPStatusGoing      = "going"
+	RSVPStatusMaybe      = "maybe"
+	RSVPStatusNotGoing   = "not_going"
+	RSVPStatusWaitlisted = "waitlisted" // Wanted to go but the session was full
+)
+
+type SessionRSVP struct {
+	ID           *uuid.UUID            `json:"id"`
+	Session      *MovieSession         `json:"session,omitempty"`
+	SessionID    *uuid.UUID            `json:"session_id" gorm:"uniqueIndex:idx_session_rsvp"`
+	User         *entity_accounts.User `json:"user,omitempty"`
+	UserID       int                   `json:"user_id" gorm:"uniqueIndex:idx_session_rsvp"`
+	Status       string                `json:"status"`
+	WaitlistedAt *time.Time            `json:"waitlisted_at"` // Orders the waitlist
+	RecordedAt   *time.Time            `json:"-"`             // When the attended session went to the user's watch history
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+func (c *SessionRSVP) TableName() string {
+	return "crew_session_rsvps"
+}
+
+func (c *SessionRSVP) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	c.ID = &ID
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// SessionRSVPSummary is the computed headcount of a session.
+type SessionRSVPSummary struct {
+	SessionID  *uuid.UUID              `json:"session_id"`
+	Capacity   int                     `json:"capacity"`
+	SpotsLeft  *int                    `json:"spots_left"` // nil when the session has no capacity limit
+	Going      int                     `json:"going"`
+	Maybe      int                     `json:"maybe"`
+	NotGoing   int                     `json:"not_going"`
+	Waitlisted int                     `json:"waitlisted"`
+	MyStatus   string                  `json:"my_status"`
+	Responses  []*SessionRSVP          `json:"responses"`
+	NoResponse []*entity_accounts.User `json:"no_response"`
+}
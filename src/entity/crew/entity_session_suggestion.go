@@ -0,0 +1,18 @@
+package entity_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+)
+
+// SessionSuggestion is a computed (not persisted) candidate slot for a
+// movie session, ranked by Score.
+type SessionSuggestion struct {
+	StartAt          time.Time               `json:"start_at"`
+	EndAt            time.Time               `json:"end_at"`
+	Score            float64                 `json:"score"`
+	AvailableCount   int                     `json:"available_count"`
+	AvailableMembers []*entity_accounts.User `json:"available_members"`
+	MissingMembers   []*entity_accounts.User `json:"missing_members"`
+	Reasons          []string                `json:"reasons"`
+}
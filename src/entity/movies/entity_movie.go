@@ -0,0 +1,36 @@
+package entity_movies
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Movie is an entry of the local catalog, imported from TMDB and IMDb dumps.
+// A movie is identified by its external IDs; either may be missing.
+type Movie struct {
+	ID             *uuid.UUID `json:"id"`
+	TMDBID         *int       `json:"tmdb_id" gorm:"column:tmdb_id;uniqueIndex"`
+	IMDbID         *string    `json:"imdb_id" gorm:"column:imdb_id;uniqueIndex"`
+	Title          string     `json:"title"`
+	OriginalTitle  string     `json:"original_title"`
+	Year           *int       `json:"year" gorm:"index"`
+	RuntimeMinutes *int       `json:"runtime_minutes"`
+	Genres         []string   `json:"genres" gorm:"type:jsonb;serializer:json"`
+	PosterPath     *string    `json:"poster_path"` // Relative TMDB image path, e.g. /abc.jpg
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (m *Movie) TableName() string {
+	return "movies_movies"
+}
+
+func (m *Movie) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	m.ID = &ID
+	m.CreatedAt = time.Now()
+	m.UpdatedAt = time.Now()
+	return nil
+}
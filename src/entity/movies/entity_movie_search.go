@@ -0,0 +1,23 @@
+package entity_movies
+
+// MovieFilter narrows a catalog search. Nil and empty fields are ignored.
+type MovieFilter struct {
+	Query      string
+	Year       *int
+	YearFrom   *int
+	YearTo     *int
+	Genre      string
+	RuntimeMin *int
+	RuntimeMax *int
+	Page       int // 1-based
+	PageSize   int
+}
+
+// MoviePage is one page of search results.
+type MoviePage struct {
+	Movies   []*Movie `json:"movies"`
+	Total    int64    `json:"total"`
+	Page     int      `json:"page"`
+	PageSize int      `json:"page_size"`
+	Fuzzy    bool     `json:"fuzzy"` // Matched by title similarity because full-text search found nothing
+}
@@ -0,0 +1,52 @@
+package entity_movies
+
+import (
+	entity_accounts "app/entity/accounts"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	MinRating = 0.5
+	MaxRating = 5.0
+)
+
+// WatchEntry records that a user watched a movie, added by hand or from a
+// crew session the user attended. A user has one entry per movie; watching
+// it again moves WatchedAt forward.
+type WatchEntry struct {
+	ID        *uuid.UUID            `json:"id"`
+	User      *entity_accounts.User `json:"user,omitempty"`
+	UserID    int                   `json:"user_id" gorm:"uniqueIndex:idx_watch_entry_user_movie"`
+	Movie     *Movie                `json:"movie,omitempty"`
+	MovieID   *uuid.UUID            `json:"movie_id" gorm:"uniqueIndex:idx_watch_entry_user_movie;index"`
+	SessionID *uuid.UUID            `json:"session_id"` // Crew session it was watched in, nil when added by hand
+	WatchedAt *time.Time            `json:"watched_at"`
+	Rating    *float64              `json:"rating"` // Stars from 0.5 to 5 in halves, nil when not rated
+	Review    string                `json:"review"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+func (w *WatchEntry) TableName() string {
+	return "movies_watch_entries"
+}
+
+func (w *WatchEntry) BeforeCreate(tx *gorm.DB) (err error) {
+	ID := uuid.New()
+	w.ID = &ID
+	w.CreatedAt = time.Now()
+	w.UpdatedAt = time.Now()
+	return nil
+}
+
+// MovieRatings aggregates the watch entries of a group of users for a movie.
+type MovieRatings struct {
+	Movie   *Movie        `json:"movie"`
+	Average *float64      `json:"average"` // nil when nobody rated it
+	Ratings int           `json:"ratings"`
+	Watched int           `json:"watched"`
+	Entries []*WatchEntry `json:"entries"`
+}
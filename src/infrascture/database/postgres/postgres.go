@@ -3,6 +3,10 @@ package database_postgres
 import (
 	"app/conf"
 	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	repository_accounts "app/infrascture/database/postgres/repository/accounts"
+	repository_movies "app/infrascture/database/postgres/repository/movies"
 	"fmt"
 	"log"
 
@@ -34,7 +38,34 @@ func RunMigrations(DB *gorm.DB) {
 	}
 
 	DB.AutoMigrate(&entity_accounts.User{})
-	DB.AutoMigrate(&entity_accounts.UserPix{})
+	if err := DB.AutoMigrate(&entity_accounts.UserPix{}); err != nil {
+		log.Printf("Could not migrate pix keys: %v", err)
+	}
+	if err := repository_accounts.MigrateUserPixKeys(DB); err != nil {
+		log.Printf("Could not migrate pix keys: %v", err)
+	}
+	if err := repository_accounts.MigrateDayOffSeries(DB); err != nil {
+		log.Printf("Could not migrate day off series: %v", err)
+	}
 	DB.AutoMigrate(&entity_accounts.UserDayOff{})
 
+	DB.AutoMigrate(&entity_movies.Movie{})
+	if err := repository_movies.MigrateSearch(DB); err != nil {
+		log.Printf("Could not set up movie search: %v", err)
+	}
+
+	DB.AutoMigrate(&entity_crew.Crew{})
+	DB.AutoMigrate(&entity_crew.CrewMember{})
+	DB.AutoMigrate(&entity_crew.CrewInvite{})
+	DB.AutoMigrate(&entity_crew.MovieSession{})
+	DB.AutoMigrate(&entity_crew.SessionRSVP{})
+	DB.AutoMigrate(&entity_crew.SessionExpense{})
+	DB.AutoMigrate(&entity_crew.ExpenseShare{})
+	DB.AutoMigrate(&entity_crew.CrewPayment{})
+	DB.AutoMigrate(&entity_crew.CrewWatchlistItem{})
+	DB.AutoMigrate(&entity_crew.SessionPoll{})
+	DB.AutoMigrate(&entity_crew.PollCandidate{})
+	DB.AutoMigrate(&entity_crew.PollBallot{})
+	DB.AutoMigrate(&entity_movies.WatchEntry{})
+
 }
@@ -8,6 +8,51 @@ import (
 	"gorm.io/gorm"
 )
 
+// MigrateDayOffSeries converts the series stored with a row per occurrence
+// into series expanded on read. The first row stays as a series with a
+// single occurrence and the other rows become overrides of it, so the
+// occurrences that were moved or deleted stay that way. It must run before
+// the table is auto migrated: only tables without recurrence_id hold rows
+// to convert, so it runs once.
+func MigrateDayOffSeries(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entity_accounts.UserDayOff{}) || migrator.HasColumn(&entity_accounts.UserDayOff{}, "RecurrenceID") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&entity_accounts.UserDayOff{}); err != nil {
+			return err
+		}
+		if err := tx.Model(&entity_accounts.UserDayOff{}).Where("rrule IS NULL").Update("rrule", "").Error; err != nil {
+			return err
+		}
+		// The legacy repeat fields are rewritten to match the new rule
+		materialized := tx.Model(&entity_accounts.UserDayOff{}).
+			Select("day_off_father_id").
+			Where("day_off_father_id IS NOT NULL")
+		if err := tx.Model(&entity_accounts.UserDayOff{}).
+			Where("id IN (?)", materialized).
+			Updates(map[string]interface{}{
+				"rrule":          "FREQ=DAILY;COUNT=1",
+				"repeat":         true,
+				"repeat_type":    entity_accounts.RepeatTypeDaily,
+				"repeat_value":   "0",
+				"recurrence_end": gorm.Expr("end_hour"),
+			}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity_accounts.UserDayOff{}).
+			Where("day_off_father_id IS NOT NULL").
+			Updates(map[string]interface{}{
+				"rrule":         "",
+				"repeat":        false,
+				"repeat_type":   "",
+				"repeat_value":  "",
+				"recurrence_id": gorm.Expr("init_hour"),
+			}).Error
+	})
+}
+
 type userDayOffRepository struct {
 	DB *gorm.DB
 }
@@ -20,15 +65,6 @@ func (r *userDayOffRepository) Create(dayOff *entity_accounts.UserDayOff) error
 	return r.DB.Create(dayOff).Error
 }
 
-func (r *userDayOffRepository) CreateBatch(dayOffs []*entity_accounts.UserDayOff) error {
-	for _, dayOff := range dayOffs {
-		if err := r.DB.Create(dayOff).Error; err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func (r *userDayOffRepository) FindByIdAndOwner(id uuid.UUID, ownerID int) (*entity_accounts.UserDayOff, error) {
 	var dayOff entity_accounts.UserDayOff
 	if err := r.DB.Where("id = ? AND owner_id = ?", id, ownerID).First(&dayOff).Error; err != nil {
@@ -50,9 +86,13 @@ func (r *userDayOffRepository) FindAllByOwnerWithFilter(ownerID int, startDate,
 	query := r.DB.Where("owner_id = ?", ownerID)
 
 	if startDate != nil && endDate != nil {
-		// Filter day-offs that overlap with the date range
 		// A day-off overlaps if: init_hour < endDate AND end_hour > startDate
-		query = query.Where("init_hour < ? AND end_hour > ?", endDate, startDate)
+		// A series overlaps if it starts before endDate and its last
+		// occurrence, if it has one, ends after startDate
+		query = query.Where(
+			"(rrule = '' AND init_hour < ? AND end_hour > ?) OR (rrule <> '' AND init_hour < ? AND (recurrence_end IS NULL OR recurrence_end > ?))",
+			endDate, startDate, endDate, startDate,
+		)
 	}
 
 	if err := query.Order("init_hour ASC").Find(&dayOffs).Error; err != nil {
@@ -61,18 +101,42 @@ func (r *userDayOffRepository) FindAllByOwnerWithFilter(ownerID int, startDate,
 	return dayOffs, nil
 }
 
-func (r *userDayOffRepository) FindFutureByName(fatherID uuid.UUID, fromDate time.Time, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+func (r *userDayOffRepository) FindAllByFather(fatherID uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
 	var dayOffs []*entity_accounts.UserDayOff
-	// Check init_hour >= fromDate. Using InitHour which is *time.Time.
-	if err := r.DB.Where("owner_id = ? AND day_off_father_id = ? AND init_hour >= ?", ownerID, fatherID, fromDate).Find(&dayOffs).Error; err != nil {
+	if err := r.DB.Where("owner_id = ? AND day_off_father_id = ?", ownerID, fatherID).Find(&dayOffs).Error; err != nil {
 		return nil, err
 	}
 	return dayOffs, nil
 }
 
-func (r *userDayOffRepository) FindAllByFather(fatherID uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+func (r *userDayOffRepository) FindAllByFathers(fatherIDs []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
 	var dayOffs []*entity_accounts.UserDayOff
-	if err := r.DB.Where("owner_id = ? AND day_off_father_id = ?", ownerID, fatherID).Find(&dayOffs).Error; err != nil {
+	if len(fatherIDs) == 0 {
+		return dayOffs, nil
+	}
+	if err := r.DB.Where("owner_id = ? AND day_off_father_id IN ?", ownerID, fatherIDs).Find(&dayOffs).Error; err != nil {
+		return nil, err
+	}
+	return dayOffs, nil
+}
+
+func (r *userDayOffRepository) FindAllByIdsAndOwner(ids []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	var dayOffs []*entity_accounts.UserDayOff
+	if len(ids) == 0 {
+		return dayOffs, nil
+	}
+	if err := r.DB.Where("owner_id = ? AND id IN ?", ownerID, ids).Find(&dayOffs).Error; err != nil {
+		return nil, err
+	}
+	return dayOffs, nil
+}
+
+func (r *userDayOffRepository) FindAllByICalUIDs(uids []string, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	var dayOffs []*entity_accounts.UserDayOff
+	if len(uids) == 0 {
+		return dayOffs, nil
+	}
+	if err := r.DB.Where("owner_id = ? AND ical_uid IN ?", ownerID, uids).Find(&dayOffs).Error; err != nil {
 		return nil, err
 	}
 	return dayOffs, nil
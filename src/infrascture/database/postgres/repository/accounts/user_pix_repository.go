@@ -2,11 +2,84 @@ package repository_accounts
 
 import (
 	entity_accounts "app/entity/accounts"
+	"app/utils/pix"
+	"errors"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// MigrateUserPixKeys brings the keys saved before validation existed in
+// line with the unique indexes and then creates them: keys are normalized,
+// duplicates of the same owner are dropped and every owner ends with exactly
+// one primary key. Once the indexes exist it does nothing.
+func MigrateUserPixKeys(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&entity_accounts.UserPix{}, "idx_user_pix_owner_key") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var userPixs []*entity_accounts.UserPix
+		if err := tx.Order("owner_id, is_primary DESC, created_at").Find(&userPixs).Error; err != nil {
+			return err
+		}
+
+		keptKeys := map[int]map[string]bool{}
+		for _, userPix := range userPixs {
+			keyType, key := normalizeLegacyKey(userPix)
+			if keptKeys[userPix.OwnerID] == nil {
+				keptKeys[userPix.OwnerID] = map[string]bool{}
+			} else if keptKeys[userPix.OwnerID][key] {
+				if err := tx.Delete(&entity_accounts.UserPix{}, "id = ?", userPix.ID).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			// Primaries come first, so the owner's first key keeps the flag
+			isPrimary := len(keptKeys[userPix.OwnerID]) == 0
+			keptKeys[userPix.OwnerID][key] = true
+
+			if key == userPix.PixKey && keyType == userPix.KeyType && isPrimary == userPix.IsPrimary {
+				continue
+			}
+			if err := tx.Model(&entity_accounts.UserPix{}).Where("id = ?", userPix.ID).Updates(map[string]interface{}{
+				"pix_key":    key,
+				"key_type":   keyType,
+				"is_primary": isPrimary,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		statements := []string{
+			"CREATE UNIQUE INDEX IF NOT EXISTS idx_user_pix_owner_key ON accounts_user_pix (owner_id, pix_key)",
+			"CREATE UNIQUE INDEX IF NOT EXISTS idx_user_pix_owner_primary ON accounts_user_pix (owner_id) WHERE is_primary",
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// normalizeLegacyKey returns the key type and key the way the use case
+// stores them now. Keys that do not validate are kept as they were, only
+// trimmed, since dropping them would lose the owner's data.
+func normalizeLegacyKey(userPix *entity_accounts.UserPix) (string, string) {
+	keyType := strings.ToLower(strings.TrimSpace(userPix.KeyType))
+	if !pix.IsValidKeyType(keyType) {
+		keyType = pix.DetectKeyType(userPix.PixKey)
+	}
+	key, err := pix.NormalizeKey(keyType, userPix.PixKey)
+	if err != nil {
+		return userPix.KeyType, strings.TrimSpace(userPix.PixKey)
+	}
+	return keyType, key
+}
+
 type userPixRepository struct {
 	DB *gorm.DB
 }
@@ -15,8 +88,25 @@ func NewUserPixRepository(db *gorm.DB) *userPixRepository {
 	return &userPixRepository{DB: db}
 }
 
+// clearPrimary unsets the primary flag of the owner's other keys so the
+// given key can take it.
+func clearPrimary(tx *gorm.DB, userPix *entity_accounts.UserPix) error {
+	query := tx.Model(&entity_accounts.UserPix{}).Where("owner_id = ? AND is_primary", userPix.OwnerID)
+	if userPix.ID != nil {
+		query = query.Where("id <> ?", userPix.ID)
+	}
+	return query.Update("is_primary", false).Error
+}
+
 func (r *userPixRepository) Create(userPix *entity_accounts.UserPix) error {
-	return r.DB.Create(userPix).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if userPix.IsPrimary {
+			if err := clearPrimary(tx, userPix); err != nil {
+				return err
+			}
+		}
+		return tx.Create(userPix).Error
+	})
 }
 
 func (r *userPixRepository) FindByIdAndOwner(idData uuid.UUID, ownerID int) (*entity_accounts.UserPix, error) {
@@ -32,18 +122,63 @@ func (r *userPixRepository) FindByIdAndOwner(idData uuid.UUID, ownerID int) (*en
 	return &userPix, nil
 }
 
+func (r *userPixRepository) FindByKeyAndOwner(pixKey string, ownerID int) (*entity_accounts.UserPix, error) {
+	var userPix entity_accounts.UserPix
+	if err := r.DB.Where("pix_key = ? AND owner_id = ?", pixKey, ownerID).First(&userPix).Error; err != nil {
+		return nil, err
+	}
+	return &userPix, nil
+}
+
+func (r *userPixRepository) FindPrimaryByOwner(ownerID int) (*entity_accounts.UserPix, error) {
+	var userPix entity_accounts.UserPix
+	if err := r.DB.Where("owner_id = ? AND is_primary", ownerID).First(&userPix).Error; err != nil {
+		return nil, err
+	}
+	return &userPix, nil
+}
+
 func (r *userPixRepository) GetAllByOwner(ownerID int) ([]*entity_accounts.UserPix, error) {
 	var userPixs []*entity_accounts.UserPix
-	if err := r.DB.Where("owner_id = ?", ownerID).Find(&userPixs).Error; err != nil {
+	if err := r.DB.Where("owner_id = ?", ownerID).Order("is_primary DESC, created_at").Find(&userPixs).Error; err != nil {
 		return nil, err
 	}
 	return userPixs, nil
 }
 
+// Delete removes the key and, if it was the primary one, hands the flag to
+// the owner's oldest remaining key.
 func (r *userPixRepository) Delete(id uuid.UUID) error {
-	return r.DB.Delete(&entity_accounts.UserPix{}, "id = ?", id).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		var userPix entity_accounts.UserPix
+		if err := tx.Where("id = ?", id).First(&userPix).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_accounts.UserPix{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if !userPix.IsPrimary {
+			return nil
+		}
+
+		var next entity_accounts.UserPix
+		err := tx.Where("owner_id = ?", userPix.OwnerID).Order("created_at").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return tx.Model(&next).Update("is_primary", true).Error
+	})
 }
 
 func (r *userPixRepository) Update(userPix *entity_accounts.UserPix) error {
-	return r.DB.Save(userPix).Error
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if userPix.IsPrimary {
+			if err := clearPrimary(tx, userPix); err != nil {
+				return err
+			}
+		}
+		return tx.Omit(clause.Associations).Save(userPix).Error
+	})
 }
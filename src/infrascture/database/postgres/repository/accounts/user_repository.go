@@ -34,6 +34,14 @@ func (r *userRepository) FindById(id int) (*entity_accounts.User, error) {
 	return &user, nil
 }
 
+func (r *userRepository) FindByCalendarToken(token string) (*entity_accounts.User, error) {
+	var user entity_accounts.User
+	if err := r.DB.Where("calendar_token = ?", token).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (r *userRepository) Update(user *entity_accounts.User) error {
 	return r.DB.Save(user).Error
 }
@@ -0,0 +1,102 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type crewInviteRepository struct {
+	DB *gorm.DB
+}
+
+func NewCrewInviteRepository(db *gorm.DB) *crewInviteRepository {
+	return &crewInviteRepository{DB: db}
+}
+
+func (r *crewInviteRepository) Create(invite *entity_crew.CrewInvite) error {
+	return r.DB.Create(invite).Error
+}
+
+func (r *crewInviteRepository) FindById(id uuid.UUID) (*entity_crew.CrewInvite, error) {
+	var invite entity_crew.CrewInvite
+	if err := r.DB.Where("id = ?", id).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *crewInviteRepository) FindByCode(code string) (*entity_crew.CrewInvite, error) {
+	var invite entity_crew.CrewInvite
+	if err := r.DB.Where("code = ?", code).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *crewInviteRepository) FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewInvite, error) {
+	var invites []*entity_crew.CrewInvite
+	if err := r.DB.Preload("Invitee").Preload("IssuedBy").Where("crew_id = ?", crewID).Order("created_at DESC").Find(&invites).Error; err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (r *crewInviteRepository) FindAllPendingByInvitee(inviteeID int) ([]*entity_crew.CrewInvite, error) {
+	var invites []*entity_crew.CrewInvite
+	if err := r.DB.Preload("Crew").Preload("IssuedBy").
+		Where("invitee_id = ? AND status = ?", inviteeID, entity_crew.InviteStatusPending).
+		Order("created_at DESC").
+		Find(&invites).Error; err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+func (r *crewInviteRepository) FindPendingByCrewAndInvitee(crewID uuid.UUID, inviteeID int) (*entity_crew.CrewInvite, error) {
+	var invite entity_crew.CrewInvite
+	if err := r.DB.Where("crew_id = ? AND invitee_id = ? AND status = ?", crewID, inviteeID, entity_crew.InviteStatusPending).First(&invite).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// Redeem uses up one use of a pending invite and adds the member it lets in,
+// in one transaction. The use is counted with a conditional update so
+// concurrent redemptions never go past MaxUses; false means the invite had
+// no use left or was no longer pending.
+func (r *crewInviteRepository) Redeem(inviteID uuid.UUID, member *entity_crew.CrewMember) (bool, error) {
+	redeemed := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		// Direct invites are accepted once; code invites when their last use goes
+		result := tx.Model(&entity_crew.CrewInvite{}).
+			Where("id = ? AND status = ? AND (max_uses = 0 OR uses < max_uses)", inviteID, entity_crew.InviteStatusPending).
+			Updates(map[string]interface{}{
+				"uses":         gorm.Expr("uses + 1"),
+				"status":       gorm.Expr("CASE WHEN code IS NULL OR (max_uses > 0 AND uses + 1 >= max_uses) THEN ? ELSE status END", entity_crew.InviteStatusAccepted),
+				"responded_at": now,
+				"updated_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := tx.Create(member).Error; err != nil {
+			return err
+		}
+		redeemed = true
+		return nil
+	})
+	return redeemed, err
+}
+
+func (r *crewInviteRepository) Update(invite *entity_crew.CrewInvite) error {
+	// Invites are usually loaded with their crew and users preloaded; never write those back.
+	return r.DB.Omit(clause.Associations).Save(invite).Error
+}
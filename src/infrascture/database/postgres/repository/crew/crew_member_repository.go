@@ -0,0 +1,44 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type crewMemberRepository struct {
+	DB *gorm.DB
+}
+
+func NewCrewMemberRepository(db *gorm.DB) *crewMemberRepository {
+	return &crewMemberRepository{DB: db}
+}
+
+func (r *crewMemberRepository) Create(member *entity_crew.CrewMember) error {
+	return r.DB.Create(member).Error
+}
+
+func (r *crewMemberRepository) FindByCrewAndUser(crewID uuid.UUID, userID int) (*entity_crew.CrewMember, error) {
+	var member entity_crew.CrewMember
+	if err := r.DB.Where("crew_id = ? AND user_id = ?", crewID, userID).First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *crewMemberRepository) FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewMember, error) {
+	var members []*entity_crew.CrewMember
+	if err := r.DB.Preload("User").Where("crew_id = ?", crewID).Order("created_at ASC").Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *crewMemberRepository) Update(member *entity_crew.CrewMember) error {
+	return r.DB.Save(member).Error
+}
+
+func (r *crewMemberRepository) Delete(id uuid.UUID) error {
+	return r.DB.Delete(&entity_crew.CrewMember{}, "id = ?", id).Error
+}
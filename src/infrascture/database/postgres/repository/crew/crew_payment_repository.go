@@ -0,0 +1,41 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type crewPaymentRepository struct {
+	DB *gorm.DB
+}
+
+func NewCrewPaymentRepository(db *gorm.DB) *crewPaymentRepository {
+	return &crewPaymentRepository{DB: db}
+}
+
+func (r *crewPaymentRepository) Create(payment *entity_crew.CrewPayment) error {
+	return r.DB.Create(payment).Error
+}
+
+func (r *crewPaymentRepository) FindByIdAndCrew(id uuid.UUID, crewID uuid.UUID) (*entity_crew.CrewPayment, error) {
+	var payment entity_crew.CrewPayment
+	if err := r.DB.Where("id = ? AND crew_id = ?", id, crewID).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *crewPaymentRepository) FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewPayment, error) {
+	var payments []*entity_crew.CrewPayment
+	if err := r.DB.Preload("FromUser").Preload("ToUser").Where("crew_id = ?", crewID).Order("created_at DESC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+func (r *crewPaymentRepository) Update(payment *entity_crew.CrewPayment) error {
+	return r.DB.Omit(clause.Associations).Save(payment).Error
+}
@@ -0,0 +1,99 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type crewRepository struct {
+	DB *gorm.DB
+}
+
+func NewCrewRepository(db *gorm.DB) *crewRepository {
+	return &crewRepository{DB: db}
+}
+
+func (r *crewRepository) Create(crew *entity_crew.Crew) error {
+	return r.DB.Create(crew).Error
+}
+
+func (r *crewRepository) FindById(id uuid.UUID) (*entity_crew.Crew, error) {
+	var crew entity_crew.Crew
+	if err := r.DB.Preload("Owner").Where("id = ?", id).First(&crew).Error; err != nil {
+		return nil, err
+	}
+	return &crew, nil
+}
+
+func (r *crewRepository) FindAllByOwner(ownerID int) ([]*entity_crew.Crew, error) {
+	var crews []*entity_crew.Crew
+	if err := r.DB.Where("owner_id = ?", ownerID).Order("created_at ASC").Find(&crews).Error; err != nil {
+		return nil, err
+	}
+	return crews, nil
+}
+
+func (r *crewRepository) FindAllByMember(userID int) ([]*entity_crew.Crew, error) {
+	var crews []*entity_crew.Crew
+	// A user belongs to a crew when they own it or have a crew_members row for it.
+	if err := r.DB.
+		Where("owner_id = ? OR id IN (?)", userID, r.DB.Model(&entity_crew.CrewMember{}).Select("crew_id").Where("user_id = ?", userID)).
+		Order("created_at ASC").
+		Find(&crews).Error; err != nil {
+		return nil, err
+	}
+	return crews, nil
+}
+
+func (r *crewRepository) Update(crew *entity_crew.Crew) error {
+	return r.DB.Save(crew).Error
+}
+
+func (r *crewRepository) Delete(id uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity_crew.CrewPayment{}, "crew_id = ?", id).Error; err != nil {
+			return err
+		}
+		expenses := tx.Model(&entity_crew.SessionExpense{}).Select("id").Where("crew_id = ?", id)
+		if err := tx.Delete(&entity_crew.ExpenseShare{}, "expense_id IN (?)", expenses).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.SessionExpense{}, "crew_id = ?", id).Error; err != nil {
+			return err
+		}
+		sessions := tx.Model(&entity_crew.MovieSession{}).Select("id").Where("crew_id = ?", id)
+		if err := tx.Delete(&entity_crew.SessionRSVP{}, "session_id IN (?)", sessions).Error; err != nil {
+			return err
+		}
+		polls := tx.Model(&entity_crew.SessionPoll{}).Select("id").Where("crew_id = ?", id)
+		if err := tx.Delete(&entity_crew.PollBallot{}, "poll_id IN (?)", polls).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.PollCandidate{}, "poll_id IN (?)", polls).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.SessionPoll{}, "crew_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.CrewWatchlistItem{}, "crew_id = ?", id).Error; err != nil {
+			return err
+		}
+		// Watch history entries are kept without their session
+		if err := tx.Model(&entity_movies.WatchEntry{}).Where("session_id IN (?)", sessions).Update("session_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.MovieSession{}, "crew_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.CrewInvite{}, "crew_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.CrewMember{}, "crew_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity_crew.Crew{}, "id = ?", id).Error
+	})
+}
@@ -0,0 +1,73 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type crewWatchlistRepository struct {
+	DB *gorm.DB
+}
+
+func NewCrewWatchlistRepository(db *gorm.DB) *crewWatchlistRepository {
+	return &crewWatchlistRepository{DB: db}
+}
+
+func (r *crewWatchlistRepository) Create(item *entity_crew.CrewWatchlistItem) error {
+	return r.DB.Create(item).Error
+}
+
+func (r *crewWatchlistRepository) FindByIdAndCrew(id uuid.UUID, crewID uuid.UUID) (*entity_crew.CrewWatchlistItem, error) {
+	var item entity_crew.CrewWatchlistItem
+	if err := r.DB.Preload("Movie").Preload("AddedBy").Where("id = ? AND crew_id = ?", id, crewID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *crewWatchlistRepository) FindByCrewAndMovie(crewID uuid.UUID, movieID uuid.UUID) (*entity_crew.CrewWatchlistItem, error) {
+	var item entity_crew.CrewWatchlistItem
+	if err := r.DB.Where("crew_id = ? AND movie_id = ?", crewID, movieID).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *crewWatchlistRepository) FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewWatchlistItem, error) {
+	var items []*entity_crew.CrewWatchlistItem
+	if err := r.DB.Preload("Movie").Preload("AddedBy").Where("crew_id = ?", crewID).Order("position ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *crewWatchlistRepository) Update(item *entity_crew.CrewWatchlistItem) error {
+	return r.DB.Omit(clause.Associations).Save(item).Error
+}
+
+// UpdatePositions saves the position of every item in one transaction.
+func (r *crewWatchlistRepository) UpdatePositions(items []*entity_crew.CrewWatchlistItem) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := tx.Model(&entity_crew.CrewWatchlistItem{}).Where("id = ?", item.ID).Update("position", item.Position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// IsInPoll reports whether the item is a candidate of a poll, open or
+// closed.
+func (r *crewWatchlistRepository) IsInPoll(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.DB.Model(&entity_crew.PollCandidate{}).Where("watchlist_item_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *crewWatchlistRepository) Delete(id uuid.UUID) error {
+	return r.DB.Delete(&entity_crew.CrewWatchlistItem{}, "id = ?", id).Error
+}
@@ -0,0 +1,96 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type movieSessionRepository struct {
+	DB *gorm.DB
+}
+
+func NewMovieSessionRepository(db *gorm.DB) *movieSessionRepository {
+	return &movieSessionRepository{DB: db}
+}
+
+func (r *movieSessionRepository) Create(session *entity_crew.MovieSession) error {
+	return r.DB.Create(session).Error
+}
+
+func (r *movieSessionRepository) FindByIdAndCrew(id uuid.UUID, crewID uuid.UUID) (*entity_crew.MovieSession, error) {
+	var session entity_crew.MovieSession
+	if err := r.DB.Preload("Organizer").Preload("Movie").Where("id = ? AND crew_id = ?", id, crewID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *movieSessionRepository) FindAllByCrewWithFilter(crewID uuid.UUID, startDate, endDate *time.Time) ([]*entity_crew.MovieSession, error) {
+	var sessions []*entity_crew.MovieSession
+	query := r.DB.Preload("Organizer").Preload("Movie").Where("crew_id = ?", crewID)
+
+	// Sessions overlapping the range: start_at < endDate AND end_at > startDate
+	if startDate != nil {
+		query = query.Where("end_at > ?", startDate)
+	}
+	if endDate != nil {
+		query = query.Where("start_at < ?", endDate)
+	}
+
+	if err := query.Order("start_at ASC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *movieSessionRepository) Update(session *entity_crew.MovieSession) error {
+	return r.DB.Omit(clause.Associations).Save(session).Error
+}
+
+// Delete removes the session with its responses and poll. A session with
+// expenses is left as it is and Delete returns false, since payments may
+// settle the debts of its expenses.
+func (r *movieSessionRepository) Delete(id uuid.UUID) (bool, error) {
+	deleted := false
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		var expenses int64
+		if err := tx.Model(&entity_crew.SessionExpense{}).Where("session_id = ?", id).Count(&expenses).Error; err != nil {
+			return err
+		}
+		if expenses > 0 {
+			return nil
+		}
+		if err := tx.Delete(&entity_crew.SessionRSVP{}, "session_id = ?", id).Error; err != nil {
+			return err
+		}
+		polls := tx.Model(&entity_crew.SessionPoll{}).Select("id").Where("session_id = ?", id)
+		if err := tx.Delete(&entity_crew.PollBallot{}, "poll_id IN (?)", polls).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.PollCandidate{}, "poll_id IN (?)", polls).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.SessionPoll{}, "session_id = ?", id).Error; err != nil {
+			return err
+		}
+		// The picked movie goes back to the watchlist unless it was watched
+		if err := tx.Model(&entity_crew.CrewWatchlistItem{}).Where("session_id = ? AND watched_at IS NULL", id).Update("session_id", nil).Error; err != nil {
+			return err
+		}
+		// Watch history entries are kept without their session
+		if err := tx.Model(&entity_movies.WatchEntry{}).Where("session_id = ?", id).Update("session_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&entity_crew.MovieSession{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,66 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type sessionExpenseRepository struct {
+	DB *gorm.DB
+}
+
+func NewSessionExpenseRepository(db *gorm.DB) *sessionExpenseRepository {
+	return &sessionExpenseRepository{DB: db}
+}
+
+// Create inserts the expense together with its shares.
+func (r *sessionExpenseRepository) Create(expense *entity_crew.SessionExpense) error {
+	return r.DB.Create(expense).Error
+}
+
+func (r *sessionExpenseRepository) FindByIdAndSession(id uuid.UUID, sessionID uuid.UUID) (*entity_crew.SessionExpense, error) {
+	var expense entity_crew.SessionExpense
+	if err := r.DB.Preload("Payer").Preload("Shares.Debtor").Where("id = ? AND session_id = ?", id, sessionID).First(&expense).Error; err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+func (r *sessionExpenseRepository) FindAllBySession(sessionID uuid.UUID) ([]*entity_crew.SessionExpense, error) {
+	var expenses []*entity_crew.SessionExpense
+	if err := r.DB.Preload("Payer").Preload("Shares.Debtor").Where("session_id = ?", sessionID).Order("created_at ASC").Find(&expenses).Error; err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+func (r *sessionExpenseRepository) FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.SessionExpense, error) {
+	var expenses []*entity_crew.SessionExpense
+	if err := r.DB.Preload("Shares").Where("crew_id = ?", crewID).Order("created_at ASC").Find(&expenses).Error; err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+func (r *sessionExpenseRepository) FindSharesBySessionAndDebtor(sessionID uuid.UUID, debtorID int) ([]*entity_crew.ExpenseShare, error) {
+	var shares []*entity_crew.ExpenseShare
+	if err := r.DB.Preload("Expense.Payer").
+		Joins("JOIN crew_session_expenses ON crew_session_expenses.id = crew_expense_shares.expense_id").
+		Where("crew_session_expenses.session_id = ? AND crew_expense_shares.debtor_id = ?", sessionID, debtorID).
+		Order("crew_expense_shares.created_at ASC").
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (r *sessionExpenseRepository) Delete(id uuid.UUID) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity_crew.ExpenseShare{}, "expense_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&entity_crew.SessionExpense{}, "id = ?", id).Error
+	})
+}
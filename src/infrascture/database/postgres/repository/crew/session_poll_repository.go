@@ -0,0 +1,61 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionPollRepository struct {
+	DB *gorm.DB
+}
+
+func NewSessionPollRepository(db *gorm.DB) *sessionPollRepository {
+	return &sessionPollRepository{DB: db}
+}
+
+// Create inserts the poll together with its candidates.
+func (r *sessionPollRepository) Create(poll *entity_crew.SessionPoll) error {
+	return r.DB.Create(poll).Error
+}
+
+func (r *sessionPollRepository) FindBySession(sessionID uuid.UUID) (*entity_crew.SessionPoll, error) {
+	var poll entity_crew.SessionPoll
+	err := r.DB.Preload("CreatedBy").
+		Preload("Candidates", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Candidates.WatchlistItem.Movie").
+		Where("session_id = ?", sessionID).First(&poll).Error
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+func (r *sessionPollRepository) Update(poll *entity_crew.SessionPoll) error {
+	return r.DB.Omit(clause.Associations).Save(poll).Error
+}
+
+func (r *sessionPollRepository) FindBallot(pollID uuid.UUID, voterID int) (*entity_crew.PollBallot, error) {
+	var ballot entity_crew.PollBallot
+	if err := r.DB.Where("poll_id = ? AND voter_id = ?", pollID, voterID).First(&ballot).Error; err != nil {
+		return nil, err
+	}
+	return &ballot, nil
+}
+
+func (r *sessionPollRepository) FindAllBallots(pollID uuid.UUID) ([]*entity_crew.PollBallot, error) {
+	var ballots []*entity_crew.PollBallot
+	if err := r.DB.Where("poll_id = ?", pollID).Order("created_at ASC").Find(&ballots).Error; err != nil {
+		return nil, err
+	}
+	return ballots, nil
+}
+
+func (r *sessionPollRepository) SaveBallot(ballot *entity_crew.PollBallot) error {
+	if ballot.ID == nil {
+		return r.DB.Create(ballot).Error
+	}
+	return r.DB.Save(ballot).Error
+}
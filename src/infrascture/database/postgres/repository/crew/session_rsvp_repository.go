@@ -0,0 +1,68 @@
+package repository_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type sessionRSVPRepository struct {
+	DB *gorm.DB
+}
+
+func NewSessionRSVPRepository(db *gorm.DB) *sessionRSVPRepository {
+	return &sessionRSVPRepository{DB: db}
+}
+
+func (r *sessionRSVPRepository) Create(rsvp *entity_crew.SessionRSVP) error {
+	return r.DB.Create(rsvp).Error
+}
+
+func (r *sessionRSVPRepository) FindBySessionAndUser(sessionID uuid.UUID, userID int) (*entity_crew.SessionRSVP, error) {
+	var rsvp entity_crew.SessionRSVP
+	if err := r.DB.Where("session_id = ? AND user_id = ?", sessionID, userID).First(&rsvp).Error; err != nil {
+		return nil, err
+	}
+	return &rsvp, nil
+}
+
+func (r *sessionRSVPRepository) FindAllBySession(sessionID uuid.UUID) ([]*entity_crew.SessionRSVP, error) {
+	var rsvps []*entity_crew.SessionRSVP
+	if err := r.DB.Preload("User").Where("session_id = ?", sessionID).Order("created_at ASC").Find(&rsvps).Error; err != nil {
+		return nil, err
+	}
+	return rsvps, nil
+}
+
+func (r *sessionRSVPRepository) FindWaitlistBySession(sessionID uuid.UUID) ([]*entity_crew.SessionRSVP, error) {
+	var rsvps []*entity_crew.SessionRSVP
+	if err := r.DB.Where("session_id = ? AND status = ?", sessionID, entity_crew.RSVPStatusWaitlisted).Order("waitlisted_at ASC").Find(&rsvps).Error; err != nil {
+		return nil, err
+	}
+	return rsvps, nil
+}
+
+func (r *sessionRSVPRepository) CountBySessionAndStatus(sessionID uuid.UUID, status string) (int, error) {
+	var count int64
+	if err := r.DB.Model(&entity_crew.SessionRSVP{}).Where("session_id = ? AND status = ?", sessionID, status).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *sessionRSVPRepository) Update(rsvp *entity_crew.SessionRSVP) error {
+	return r.DB.Omit(clause.Associations).Save(rsvp).Error
+}
+
+func (r *sessionRSVPRepository) Delete(id uuid.UUID) error {
+	return r.DB.Delete(&entity_crew.SessionRSVP{}, "id = ?", id).Error
+}
+
+// LockSession locks the row of a session until the transaction ends, so
+// responses to it are saved one at a time.
+func (r *sessionRSVPRepository) LockSession(sessionID uuid.UUID) error {
+	var session entity_crew.MovieSession
+	return r.DB.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("id = ?", sessionID).Take(&session).Error
+}
@@ -0,0 +1,169 @@
+package repository_movies
+
+import (
+	entity_movies "app/entity/movies"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// searchDocument is the text searched for a movie. The indexes created by
+// MigrateSearch are built on this exact expression.
+const searchDocument = "movies_unaccent(title || ' ' || original_title)"
+
+// MigrateSearch installs what catalog search needs: unaccent is only STABLE,
+// so an IMMUTABLE wrapper lets the expression indexes use it.
+func MigrateSearch(db *gorm.DB) error {
+	statements := []string{
+		"CREATE EXTENSION IF NOT EXISTS unaccent",
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE OR REPLACE FUNCTION movies_unaccent(text) RETURNS text AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT",
+		"CREATE INDEX IF NOT EXISTS idx_movies_search_portuguese ON movies_movies USING gin (to_tsvector('portuguese', " + searchDocument + "))",
+		"CREATE INDEX IF NOT EXISTS idx_movies_search_english ON movies_movies USING gin (to_tsvector('english', " + searchDocument + "))",
+		"CREATE INDEX IF NOT EXISTS idx_movies_search_trigram ON movies_movies USING gin (lower(" + searchDocument + ") gin_trgm_ops)",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type movieRepository struct {
+	DB *gorm.DB
+}
+
+func NewMovieRepository(db *gorm.DB) *movieRepository {
+	return &movieRepository{DB: db}
+}
+
+func (r *movieRepository) FindAllByExternalIDs(tmdbIDs []int, imdbIDs []string) ([]*entity_movies.Movie, error) {
+	var movies []*entity_movies.Movie
+	if len(tmdbIDs) == 0 && len(imdbIDs) == 0 {
+		return movies, nil
+	}
+
+	query := r.DB.Where("1 = 0")
+	if len(tmdbIDs) > 0 {
+		query = query.Or("tmdb_id IN ?", tmdbIDs)
+	}
+	if len(imdbIDs) > 0 {
+		query = query.Or("imdb_id IN ?", imdbIDs)
+	}
+	if err := query.Find(&movies).Error; err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// SaveBatch inserts the new movies and updates the existing ones in a
+// single transaction.
+func (r *movieRepository) SaveBatch(created []*entity_movies.Movie, updated []*entity_movies.Movie) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+		for _, movie := range updated {
+			if err := tx.Save(movie).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *movieRepository) FindById(id uuid.UUID) (*entity_movies.Movie, error) {
+	var movie entity_movies.Movie
+	if err := r.DB.Where("id = ?", id).First(&movie).Error; err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+func (r *movieRepository) FindAllByGenres(genres []string, excludeIDs []uuid.UUID, limit int) ([]*entity_movies.Movie, error) {
+	var movies []*entity_movies.Movie
+	if len(genres) == 0 {
+		return movies, nil
+	}
+
+	lowered := make([]string, len(genres))
+	for i, genre := range genres {
+		lowered[i] = strings.ToLower(genre)
+	}
+	matches := "(SELECT count(*) FROM jsonb_array_elements_text(genres) AS genre WHERE lower(genre) IN ?)"
+	query := r.DB.Where(matches+" > 0", lowered)
+	if len(excludeIDs) > 0 {
+		query = query.Where("id NOT IN ?", excludeIDs)
+	}
+	err := query.Order(clause.OrderBy{Expression: clause.Expr{SQL: matches + " DESC, year DESC NULLS LAST, title", Vars: []interface{}{lowered}}}).
+		Limit(limit).
+		Find(&movies).Error
+	if err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// Search matches the query with full-text search in Portuguese and English,
+// or by trigram word similarity when fuzzy, so typos still find the title.
+func (r *movieRepository) Search(filter entity_movies.MovieFilter, fuzzy bool) ([]*entity_movies.Movie, int64, error) {
+	query := r.DB.Model(&entity_movies.Movie{})
+	if filter.Year != nil {
+		query = query.Where("year = ?", *filter.Year)
+	}
+	if filter.YearFrom != nil {
+		query = query.Where("year >= ?", *filter.YearFrom)
+	}
+	if filter.YearTo != nil {
+		query = query.Where("year <= ?", *filter.YearTo)
+	}
+	if filter.Genre != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(genres) AS genre WHERE lower(genre) = lower(?))", filter.Genre)
+	}
+	if filter.RuntimeMin != nil {
+		query = query.Where("runtime_minutes >= ?", *filter.RuntimeMin)
+	}
+	if filter.RuntimeMax != nil {
+		query = query.Where("runtime_minutes <= ?", *filter.RuntimeMax)
+	}
+
+	order := clause.Expr{SQL: "year DESC NULLS LAST, title"}
+	if filter.Query != "" && fuzzy {
+		query = query.Where("movies_unaccent(lower(?)) <% lower("+searchDocument+")", filter.Query)
+		order = clause.Expr{
+			SQL:  "word_similarity(movies_unaccent(lower(?)), lower(" + searchDocument + ")) DESC, title",
+			Vars: []interface{}{filter.Query},
+		}
+	} else if filter.Query != "" {
+		query = query.Where(
+			"to_tsvector('portuguese', "+searchDocument+") @@ websearch_to_tsquery('portuguese', movies_unaccent(?)) OR "+
+				"to_tsvector('english', "+searchDocument+") @@ websearch_to_tsquery('english', movies_unaccent(?))",
+			filter.Query, filter.Query)
+		order = clause.Expr{
+			SQL: "ts_rank(to_tsvector('portuguese', " + searchDocument + "), websearch_to_tsquery('portuguese', movies_unaccent(?))) + " +
+				"ts_rank(to_tsvector('english', " + searchDocument + "), websearch_to_tsquery('english', movies_unaccent(?))) DESC, year DESC NULLS LAST",
+			Vars: []interface{}{filter.Query, filter.Query},
+		}
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var movies []*entity_movies.Movie
+	err := query.Order(clause.OrderBy{Expression: order}).
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&movies).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return movies, total, nil
+}
@@ -0,0 +1,118 @@
+package repository_movies
+
+import (
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type watchEntryRepository struct {
+	DB *gorm.DB
+}
+
+func NewWatchEntryRepository(db *gorm.DB) *watchEntryRepository {
+	return &watchEntryRepository{DB: db}
+}
+
+func (r *watchEntryRepository) Create(entry *entity_movies.WatchEntry) error {
+	return r.DB.Create(entry).Error
+}
+
+func (r *watchEntryRepository) FindByIdAndUser(id uuid.UUID, userID int) (*entity_movies.WatchEntry, error) {
+	var entry entity_movies.WatchEntry
+	if err := r.DB.Preload("Movie").Where("id = ? AND user_id = ?", id, userID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *watchEntryRepository) FindByUserAndMovie(userID int, movieID uuid.UUID) (*entity_movies.WatchEntry, error) {
+	var entry entity_movies.WatchEntry
+	if err := r.DB.Where("user_id = ? AND movie_id = ?", userID, movieID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *watchEntryRepository) FindAllByUser(userID int) ([]*entity_movies.WatchEntry, error) {
+	var entries []*entity_movies.WatchEntry
+	if err := r.DB.Preload("Movie").Where("user_id = ?", userID).Order("watched_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *watchEntryRepository) FindAllByUsers(userIDs []int) ([]*entity_movies.WatchEntry, error) {
+	var entries []*entity_movies.WatchEntry
+	if err := r.DB.Preload("Movie").Where("user_id IN ?", userIDs).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *watchEntryRepository) FindSimilarUsers(movieIDs []uuid.UUID, excludeUserIDs []int, limit int) ([]int, error) {
+	var userIDs []int
+	err := r.DB.Model(&entity_movies.WatchEntry{}).
+		Where("movie_id IN ? AND rating IS NOT NULL AND user_id NOT IN ?", movieIDs, excludeUserIDs).
+		Group("user_id").
+		Order("count(*) DESC, user_id").
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (r *watchEntryRepository) FindAllByMovieAndUsers(movieID uuid.UUID, userIDs []int) ([]*entity_movies.WatchEntry, error) {
+	var entries []*entity_movies.WatchEntry
+	if err := r.DB.Preload("User").Where("movie_id = ? AND user_id IN ?", movieID, userIDs).Order("watched_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *watchEntryRepository) Update(entry *entity_movies.WatchEntry) error {
+	return r.DB.Omit(clause.Associations).Save(entry).Error
+}
+
+func (r *watchEntryRepository) Delete(id uuid.UUID) error {
+	return r.DB.Delete(&entity_movies.WatchEntry{}, "id = ?", id).Error
+}
+
+func (r *watchEntryRepository) FindUnrecordedAttendances(userIDs []int, endedBefore time.Time) ([]*entity_crew.SessionRSVP, error) {
+	var rsvps []*entity_crew.SessionRSVP
+	err := r.DB.Preload("Session").
+		Joins("JOIN crew_movie_sessions ON crew_movie_sessions.id = crew_session_rsvps.session_id").
+		Where("crew_session_rsvps.user_id IN ? AND crew_session_rsvps.status = ? AND crew_session_rsvps.recorded_at IS NULL", userIDs, entity_crew.RSVPStatusGoing).
+		Where("crew_movie_sessions.movie_id IS NOT NULL AND crew_movie_sessions.end_at < ?", endedBefore).
+		Order("crew_movie_sessions.end_at ASC").
+		Find(&rsvps).Error
+	if err != nil {
+		return nil, err
+	}
+	return rsvps, nil
+}
+
+func (r *watchEntryRepository) RecordAttendance(rsvp *entity_crew.SessionRSVP, entry *entity_movies.WatchEntry) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if entry.ID == nil {
+			if err := tx.Create(entry).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Omit(clause.Associations).Save(entry).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&entity_crew.SessionRSVP{}).Where("id = ?", rsvp.ID).Update("recorded_at", now).Error; err != nil {
+			return err
+		}
+		rsvp.RecordedAt = &now
+		return nil
+	})
+}
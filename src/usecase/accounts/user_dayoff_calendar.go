@@ -0,0 +1,49 @@
+package usecase_accounts
+
+import (
+	entity_accounts "app/entity/accounts"
+	"app/utils/ical"
+
+	"github.com/google/uuid"
+)
+
+const DayOffSummary = "Day off"
+
+func dayOffEvent(dayOff *entity_accounts.UserDayOff, domain string) *ical.Event {
+	return &ical.Event{
+		UID:      dayOff.ID.String() + "@" + domain,
+		Stamp:    dayOff.UpdatedAt,
+		Start:    *dayOff.InitHour,
+		End:      *dayOff.EndHour,
+		Summary:  DayOffSummary,
+		Status:   ical.StatusConfirmed,
+		TimeZone: dayOff.TimeZone,
+	}
+}
+
+// DayOffEvents turns day offs into VEVENTs. A series becomes one event with
+// its RRULE and its cancelled occurrences as EXDATEs. Its overrides share its
+// UID and name the occurrence they replace with RECURRENCE-ID.
+func DayOffEvents(dayOffs []*entity_accounts.UserDayOff, domain string) []*ical.Event {
+	series := map[uuid.UUID]bool{}
+	for _, dayOff := range dayOffs {
+		if dayOff.RRule != "" && dayOff.DayOffFatherID == nil {
+			series[*dayOff.ID] = true
+		}
+	}
+
+	events := []*ical.Event{}
+	for _, dayOff := range dayOffs {
+		event := dayOffEvent(dayOff, domain)
+		switch {
+		case series[*dayOff.ID]:
+			event.RRule = dayOff.RRule
+			event.ExDates = dayOff.ExDates
+		case dayOff.DayOffFatherID != nil && dayOff.RecurrenceID != nil && series[*dayOff.DayOffFatherID]:
+			event.UID = dayOff.DayOffFatherID.String() + "@" + domain
+			event.RecurrenceID = dayOff.RecurrenceID
+		}
+		events = append(events, event)
+	}
+	return events
+}
@@ -0,0 +1,96 @@
+package usecase_accounts
+
+import (
+	entity_accounts "app/entity/accounts"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrNotRecurring = errors.New("day off does not repeat")
+
+// findSeries returns the series a day off id belongs to: the day off itself
+// or the series it overrides an occurrence of.
+func (u *userDayOffUseCase) findSeries(id uuid.UUID, ownerID int) (*entity_accounts.UserDayOff, error) {
+	series, err := u.repo.FindByIdAndOwner(id, ownerID)
+	if err != nil {
+		return nil, ErrDayOffNotFound
+	}
+	if series.DayOffFatherID != nil {
+		series, err = u.repo.FindByIdAndOwner(*series.DayOffFatherID, ownerID)
+		if err != nil {
+			return nil, ErrDayOffNotFound
+		}
+	}
+	if series.RRule == "" {
+		return nil, ErrNotRecurring
+	}
+	return series, nil
+}
+
+func (u *userDayOffUseCase) GetExceptions(id uuid.UUID, ownerID int) (*entity_accounts.DayOffExceptions, error) {
+	series, err := u.findSeries(id, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := u.repo.FindAllByFather(*series.ID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load day offs")
+	}
+
+	exceptions := &entity_accounts.DayOffExceptions{SeriesID: series.ID, ExDates: series.ExDates, Overrides: overrides}
+	if exceptions.ExDates == nil {
+		exceptions.ExDates = []time.Time{}
+	}
+	return exceptions, nil
+}
+
+// RestoreOccurrence brings back the occurrence of a series starting at
+// occurrence, as its rule generates it: it is no longer cancelled and its
+// override, if any, is deleted.
+func (u *userDayOffUseCase) RestoreOccurrence(id uuid.UUID, ownerID int, occurrence time.Time) error {
+	return u.inTransaction(func(tx *userDayOffUseCase) error {
+		return tx.restoreOccurrence(id, ownerID, occurrence)
+	})
+}
+
+func (u *userDayOffUseCase) restoreOccurrence(id uuid.UUID, ownerID int, occurrence time.Time) error {
+	series, err := u.findSeries(id, ownerID)
+	if err != nil {
+		return err
+	}
+	overrides, err := u.repo.FindAllByFather(*series.ID, ownerID)
+	if err != nil {
+		return fmt.Errorf("could not load day offs")
+	}
+
+	found := false
+	kept := []time.Time{}
+	for _, exDate := range series.ExDates {
+		if exDate.Equal(occurrence) {
+			found = true
+		} else {
+			kept = append(kept, exDate)
+		}
+	}
+	if found {
+		series.ExDates = kept
+		if err := u.repo.Update(series); err != nil {
+			return fmt.Errorf("could not restore occurrence")
+		}
+	}
+	for _, override := range overrides {
+		if override.RecurrenceID != nil && override.RecurrenceID.Equal(occurrence) {
+			found = true
+			if err := u.repo.DeleteById(*override.ID); err != nil {
+				return fmt.Errorf("could not restore occurrence")
+			}
+		}
+	}
+	if !found {
+		return ErrOccurrenceNotFound
+	}
+	return nil
+}
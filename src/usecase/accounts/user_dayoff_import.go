@@ -0,0 +1,253 @@
+package usecase_accounts
+
+import (
+	entity_accounts "app/entity/accounts"
+	"app/utils/ical"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidCalendar = errors.New("invalid calendar file")
+
+// importUID identifies an event among the imported day offs. Moved
+// occurrences share the UID of their series, so the RECURRENCE-ID is added.
+func importUID(event *ical.ParsedEvent) string {
+	if event.RecurrenceID == nil {
+		return event.UID
+	}
+	return event.UID + "/" + event.RecurrenceID.UTC().Format("20060102T150405Z")
+}
+
+// exportedDayOffID returns the day off a UID was exported from by the
+// calendar feed, which writes UIDs as <id>@<domain>.
+func exportedDayOffID(uid string) (uuid.UUID, bool) {
+	prefix, _, found := strings.Cut(uid, "@")
+	if !found {
+		return uuid.UUID{}, false
+	}
+	id, err := uuid.Parse(prefix)
+	return id, err == nil
+}
+
+// importSeries turns a recurring event into a series. RFC 5545 counts
+// DTSTART as an occurrence even when the rule does not generate it; the
+// series then starts at the first occurrence the rule generates and DTSTART
+// is returned as an extra occurrence. Series may repeat forever, so the
+// occurrences are only counted when the rule ends. The series repeats in the
+// time zone of DTSTART.
+func importSeries(event *ical.ParsedEvent, exDates []time.Time) (*entity_accounts.UserDayOff, *time.Time, int, error) {
+	start := event.Start
+	rule, err := parseRecurrence(event.RRule, start)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	first := rule.After(start, true)
+	if first.IsZero() {
+		return nil, nil, 0, fmt.Errorf("%w: the rule has no occurrences", ErrInvalidRecurrence)
+	}
+	rule, err = validateRecurrence(event.RRule, first)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	initHour, endHour := first, first.Add(event.End.Sub(event.Start))
+	series := &entity_accounts.UserDayOff{InitHour: &initHour, EndHour: &endHour, ExDates: []time.Time{}, TimeZone: start.Location().String()}
+	for _, exDate := range exDates {
+		series.ExDates = append(series.ExDates, exDate.UTC())
+	}
+	setRecurrence(series, rule)
+
+	var extra *time.Time
+	if !first.Equal(start) {
+		extra = &start
+	}
+	occurrences := 0
+	if series.RecurrenceEnd != nil {
+		cancelled := map[int64]bool{}
+		for _, exDate := range series.ExDates {
+			cancelled[exDate.Unix()] = true
+		}
+		next := rule.Iterator()
+		for occurrence, ok := next(); ok; occurrence, ok = next() {
+			if !cancelled[occurrence.Unix()] {
+				occurrences++
+			}
+		}
+		if extra != nil {
+			occurrences++
+		}
+	}
+	return series, extra, occurrences, nil
+}
+
+// Import creates day offs from the VEVENTs of an iCalendar file. Times
+// without a time zone are read in the owner's. A recurring
+// event becomes a series with its RRULE and EXDATEs, and its moved
+// occurrences become overrides of it.
+// Events already imported, or exported from this account's own feed, are
+// skipped. On a dry run nothing is saved, and otherwise either every event
+// is saved or none is.
+func (u *userDayOffUseCase) Import(calendar io.Reader, ownerID int, dryRun bool) (*entity_accounts.DayOffImportReport, error) {
+	loc, err := u.location("", ownerID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := ical.Parse(calendar, loc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendar, err)
+	}
+
+	var report *entity_accounts.DayOffImportReport
+	err = u.inTransaction(func(tx *userDayOffUseCase) error {
+		report, err = tx.importEvents(events, ownerID, dryRun)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (u *userDayOffUseCase) importEvents(events []*ical.ParsedEvent, ownerID int, dryRun bool) (*entity_accounts.DayOffImportReport, error) {
+	report := &entity_accounts.DayOffImportReport{DryRun: dryRun, Events: []*entity_accounts.DayOffImportEvent{}}
+	results := map[*ical.ParsedEvent]*entity_accounts.DayOffImportEvent{}
+	uids := []string{}
+	exportedIDs := []uuid.UUID{}
+	cancelled := map[string][]time.Time{} // Cancelled occurrences of each series
+	for _, event := range events {
+		result := &entity_accounts.DayOffImportEvent{Index: event.Index, UID: event.UID, Summary: event.Summary}
+		report.Events = append(report.Events, result)
+		results[event] = result
+		if event.Err != nil {
+			continue
+		}
+
+		uids = append(uids, importUID(event), event.UID)
+		if id, ok := exportedDayOffID(event.UID); ok {
+			exportedIDs = append(exportedIDs, id)
+		}
+		if event.RecurrenceID != nil && event.Status == "CANCELLED" {
+			cancelled[event.UID] = append(cancelled[event.UID], *event.RecurrenceID)
+		}
+	}
+
+	imported := map[string]*entity_accounts.UserDayOff{}
+	existing, err := u.repo.FindAllByICalUIDs(uids, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not check imported day offs")
+	}
+	for _, dayOff := range existing {
+		if imported[*dayOff.ICalUID] == nil || dayOff.DayOffFatherID == nil {
+			imported[*dayOff.ICalUID] = dayOff // The father of a series
+		}
+	}
+	exported := map[uuid.UUID]bool{}
+	existing, err = u.repo.FindAllByIdsAndOwner(exportedIDs, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not check imported day offs")
+	}
+	for _, dayOff := range existing {
+		exported[*dayOff.ID] = true
+	}
+
+	// Series go first so the occurrences they replace can be linked to them
+	ordered := append([]*ical.ParsedEvent{}, events...)
+	sort.SliceStable(ordered, func(a, b int) bool {
+		return ordered[a].RecurrenceID == nil && ordered[b].RecurrenceID != nil
+	})
+
+	seen := map[string]bool{}
+	fathers := map[string]*entity_accounts.UserDayOff{} // Series created by this import, by UID
+	for _, event := range ordered {
+		result := results[event]
+		if event.Err != nil {
+			result.Status, result.Reason = entity_accounts.ImportStatusError, event.Err.Error()
+			continue
+		}
+
+		uid := importUID(event)
+		exportedID, fromFeed := exportedDayOffID(event.UID)
+		switch {
+		case seen[uid]:
+			result.Status, result.Reason = entity_accounts.ImportStatusSkipped, "duplicate UID in the file"
+		case imported[uid] != nil:
+			result.Status, result.Reason = entity_accounts.ImportStatusSkipped, "already imported"
+		case fromFeed && exported[exportedID]:
+			result.Status, result.Reason = entity_accounts.ImportStatusSkipped, "exported from this account"
+		case event.Status == "CANCELLED":
+			result.Status, result.Reason = entity_accounts.ImportStatusSkipped, "cancelled"
+		}
+		seen[uid] = true
+		if result.Status != "" {
+			continue
+		}
+
+		duration := event.End.Sub(event.Start)
+		start, end := event.Start, event.End
+		dayOff := &entity_accounts.UserDayOff{InitHour: &start, EndHour: &end, ICalUID: &uid, TimeZone: start.Location().String()}
+		var extra *time.Time
+		result.Occurrences = 1
+		if event.RRule != "" && event.RecurrenceID == nil {
+			series, extraStart, occurrences, err := importSeries(event, append(event.ExDates, cancelled[event.UID]...))
+			if err != nil {
+				result.Status, result.Reason = entity_accounts.ImportStatusError, err.Error()
+				continue
+			}
+			dayOff, extra, result.Occurrences = series, extraStart, occurrences
+			dayOff.ICalUID = &uid
+			result.RRule = dayOff.RRule
+		}
+		dayOff.OwnerID = ownerID
+
+		result.Status = entity_accounts.ImportStatusCreated
+		result.InitHour, result.EndHour = dayOff.InitHour, dayOff.EndHour
+		if event.RecurrenceID == nil {
+			fathers[event.UID] = dayOff
+		} else {
+			// A moved occurrence overrides its series when it is known
+			series := fathers[event.UID]
+			if series == nil {
+				series = imported[event.UID]
+			}
+			if series != nil {
+				recurrenceID := event.RecurrenceID.UTC()
+				dayOff.DayOffFatherID, dayOff.RecurrenceID = series.ID, &recurrenceID
+				if series.DayOffFatherID != nil {
+					dayOff.DayOffFatherID = series.DayOffFatherID
+				}
+			}
+		}
+		if dryRun {
+			continue
+		}
+
+		if err := u.repo.Create(dayOff); err != nil {
+			return nil, fmt.Errorf("could not create day off")
+		}
+		if extra != nil {
+			extraEnd := extra.Add(duration)
+			override := &entity_accounts.UserDayOff{InitHour: extra, EndHour: &extraEnd, OwnerID: ownerID, ICalUID: &uid, DayOffFatherID: dayOff.ID, RecurrenceID: extra, TimeZone: dayOff.TimeZone}
+			if err := u.repo.Create(override); err != nil {
+				return nil, fmt.Errorf("could not create day off")
+			}
+		}
+	}
+
+	for _, result := range report.Events {
+		switch result.Status {
+		case entity_accounts.ImportStatusCreated:
+			report.Created++
+		case entity_accounts.ImportStatusSkipped:
+			report.Skipped++
+		case entity_accounts.ImportStatusError:
+			report.Errors++
+		}
+	}
+	return report, nil
+}
@@ -2,6 +2,7 @@ package usecase_accounts
 
 import (
 	entity_accounts "app/entity/accounts"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,21 +10,29 @@ import (
 
 type IRepositoryUserDayOff interface {
 	Create(dayOff *entity_accounts.UserDayOff) error
-	CreateBatch(dayOffs []*entity_accounts.UserDayOff) error
 	FindByIdAndOwner(id uuid.UUID, ownerID int) (*entity_accounts.UserDayOff, error)
 	FindAllByOwner(ownerID int) ([]*entity_accounts.UserDayOff, error)
 	FindAllByOwnerWithFilter(ownerID int, startDate, endDate *time.Time) ([]*entity_accounts.UserDayOff, error)
-	FindFutureByName(fatherID uuid.UUID, fromDate time.Time, ownerID int) ([]*entity_accounts.UserDayOff, error)
 	FindAllByFather(fatherID uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error)
+	FindAllByFathers(fatherIDs []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error)
+	FindAllByIdsAndOwner(ids []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error)
+	FindAllByICalUIDs(uids []string, ownerID int) ([]*entity_accounts.UserDayOff, error)
 	DeleteById(id uuid.UUID) error
 	DeleteBatch(ids []uuid.UUID) error
 	Update(dayOff *entity_accounts.UserDayOff) error
 }
 
+// TransactionUserDayOff runs fn with a repository bound to a transaction,
+// which is committed when fn returns nil and rolled back otherwise.
+type TransactionUserDayOff func(fn func(repo IRepositoryUserDayOff) error) error
+
 type IUseCaseUserDayOff interface {
 	Create(dayOff *entity_accounts.UserDayOff, ownerID int) error
-	Update(dayOff *entity_accounts.UserDayOff, ownerID int, mode string) error
-	Delete(id uuid.UUID, ownerID int, mode string) error
+	Update(dayOff *entity_accounts.UserDayOff, ownerID int, mode string, occurrence *time.Time) error
+	Delete(id uuid.UUID, ownerID int, mode string, occurrence *time.Time) error
 	GetById(id uuid.UUID, ownerID int) (*entity_accounts.UserDayOff, error)
-	GetAll(ownerID int, filterType string, year, week, month int) ([]*entity_accounts.UserDayOff, error)
+	GetAll(ownerID int, filterType string, year, week, month int, timeZone string) ([]*entity_accounts.UserDayOff, error)
+	GetExceptions(id uuid.UUID, ownerID int) (*entity_accounts.DayOffExceptions, error)
+	RestoreOccurrence(id uuid.UUID, ownerID int, occurrence time.Time) error
+	Import(calendar io.Reader, ownerID int, dryRun bool) (*entity_accounts.DayOffImportReport, error)
 }
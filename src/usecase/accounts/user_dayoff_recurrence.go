@@ -0,0 +1,221 @@
+package usecase_accounts
+
+import (
+	entity_accounts "app/entity/accounts"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/teambition/rrule-go"
+)
+
+var (
+	ErrInvalidRecurrence  = errors.New("invalid recurrence")
+	ErrOccurrenceNotFound = errors.New("occurrence not found")
+)
+
+var repeatTypeFrequencies = map[string]rrule.Frequency{
+	entity_accounts.RepeatTypeDaily:   rrule.DAILY,
+	entity_accounts.RepeatTypeWeekly:  rrule.WEEKLY,
+	entity_accounts.RepeatTypeMonthly: rrule.MONTHLY,
+	entity_accounts.RepeatTypeYearly:  rrule.YEARLY,
+}
+
+// legacyRecurrence converts repeat_type and repeat_value, the number of
+// repetitions after the first day off, to an RRULE.
+func legacyRecurrence(repeatType string, repeatValue string) (string, error) {
+	frequency, ok := repeatTypeFrequencies[repeatType]
+	if !ok {
+		return "", fmt.Errorf("%w: repeat_type must be daily, weekly, monthly or yearly", ErrInvalidRecurrence)
+	}
+	repeats, err := strconv.Atoi(repeatValue)
+	if err != nil || repeats < 1 {
+		return "", fmt.Errorf("%w: repeat_value must be a positive number of repetitions", ErrInvalidRecurrence)
+	}
+	return fmt.Sprintf("FREQ=%s;COUNT=%d", frequency, repeats+1), nil
+}
+
+// parseRecurrence reads an RRULE value, with or without the RRULE: prefix,
+// for a series starting at start. An UNTIL date without a time includes the
+// whole day.
+func parseRecurrence(value string, start time.Time) (*rrule.RRule, error) {
+	value = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:")
+	option, err := rrule.StrToROptionInLocation(value, start.Location())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	if !option.Dtstart.IsZero() {
+		return nil, fmt.Errorf("%w: DTSTART is taken from init_hour", ErrInvalidRecurrence)
+	}
+	for _, part := range strings.Split(value, ";") {
+		if until, found := strings.CutPrefix(part, "UNTIL="); found && len(until) == len(rrule.DateFormat) {
+			option.Until = option.Until.AddDate(0, 0, 1).Add(-time.Second)
+		}
+	}
+
+	option.Dtstart = start
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+	}
+	return rule, nil
+}
+
+// seriesLocation returns the time zone a series repeats in.
+func seriesLocation(series *entity_accounts.UserDayOff) *time.Location {
+	loc, err := loadTimeZone(series.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// seriesRule returns the recurrence of a series, expanded in its time zone so
+// occurrences keep their wall-clock time across DST changes, or nil for day
+// offs that do not repeat.
+func seriesRule(series *entity_accounts.UserDayOff) *rrule.RRule {
+	if series.RRule == "" || series.DayOffFatherID != nil {
+		return nil
+	}
+	rule, err := parseRecurrence(series.RRule, series.InitHour.In(seriesLocation(series)))
+	if err != nil {
+		return nil
+	}
+	return rule
+}
+
+// validateRecurrence checks the RRULE of a series starting at start, which
+// must be its first occurrence. Series may repeat forever.
+func validateRecurrence(value string, start time.Time) (*rrule.RRule, error) {
+	rule, err := parseRecurrence(value, start)
+	if err != nil {
+		return nil, err
+	}
+
+	option := rule.OrigOptions
+	if _, ok := repeatTypeFrequencies[strings.ToLower(option.Freq.String())]; !ok {
+		return nil, fmt.Errorf("%w: FREQ must be DAILY, WEEKLY, MONTHLY or YEARLY", ErrInvalidRecurrence)
+	}
+	if !option.Until.IsZero() && option.Until.Before(start) {
+		return nil, fmt.Errorf("%w: UNTIL is before init_hour", ErrInvalidRecurrence)
+	}
+	if first := rule.After(start, true); !first.Equal(start) {
+		return nil, fmt.Errorf("%w: init_hour must be the first occurrence of the rule", ErrInvalidRecurrence)
+	}
+	return rule, nil
+}
+
+// setRecurrence stores the rule on a series, along with the legacy repeat
+// fields for older clients and the end of its last occurrence.
+func setRecurrence(series *entity_accounts.UserDayOff, rule *rrule.RRule) {
+	option := rule.OrigOptions
+	series.Repeat = true
+	series.RRule = option.RRuleString()
+	series.RepeatType = strings.ToLower(option.Freq.String())
+	series.RepeatValue = ""
+	if option.Count > 0 {
+		series.RepeatValue = strconv.Itoa(option.Count - 1)
+	}
+
+	series.RecurrenceEnd = nil
+	if option.Count == 0 && option.Until.IsZero() {
+		return
+	}
+	last := time.Time{}
+	next := rule.Iterator()
+	for start, ok := next(); ok; start, ok = next() {
+		last = start
+	}
+	if !last.IsZero() {
+		end := last.Add(series.EndHour.Sub(*series.InitHour))
+		series.RecurrenceEnd = &end
+	}
+}
+
+// isOccurrence reports whether the rule of a series generates start and it
+// was not cancelled.
+func isOccurrence(series *entity_accounts.UserDayOff, start time.Time) bool {
+	rule := seriesRule(series)
+	if rule == nil || !rule.After(start, true).Equal(start) {
+		return false
+	}
+	for _, exDate := range series.ExDates {
+		if exDate.Equal(start) {
+			return false
+		}
+	}
+	return true
+}
+
+// expandSeries returns the occurrences of a series overlapping [from, to),
+// leaving out the cancelled ones and the ones replaced by overrides. Each
+// occurrence is a copy of the series with its RecurrenceID set.
+func expandSeries(series *entity_accounts.UserDayOff, replaced []time.Time, from, to time.Time) []*entity_accounts.UserDayOff {
+	rule := seriesRule(series)
+	if rule == nil {
+		return nil
+	}
+
+	skip := map[int64]bool{}
+	for _, start := range replaced {
+		skip[start.Unix()] = true
+	}
+	for _, exDate := range series.ExDates {
+		skip[exDate.Unix()] = true
+	}
+	duration := series.EndHour.Sub(*series.InitHour)
+	occurrences := []*entity_accounts.UserDayOff{}
+	for _, start := range rule.Between(from.Add(-duration), to, false) {
+		if skip[start.Unix()] {
+			continue
+		}
+		initHour, endHour := start, start.Add(duration)
+		occurrence := *series
+		occurrence.InitHour, occurrence.EndHour, occurrence.RecurrenceID = &initHour, &endHour, &initHour
+		occurrences = append(occurrences, &occurrence)
+	}
+	return occurrences
+}
+
+// DayOffsBetween returns the day offs of an owner overlapping [from, to),
+// with each series expanded into its occurrences.
+func DayOffsBetween(repo IRepositoryUserDayOff, ownerID int, from, to time.Time) ([]*entity_accounts.UserDayOff, error) {
+	stored, err := repo.FindAllByOwnerWithFilter(ownerID, &from, &to)
+	if err != nil {
+		return nil, fmt.Errorf("could not load day offs")
+	}
+
+	dayOffs := []*entity_accounts.UserDayOff{}
+	series := []*entity_accounts.UserDayOff{}
+	seriesIDs := []uuid.UUID{}
+	for _, dayOff := range stored {
+		if dayOff.RRule != "" && dayOff.DayOffFatherID == nil {
+			series = append(series, dayOff)
+			seriesIDs = append(seriesIDs, *dayOff.ID)
+		} else {
+			dayOffs = append(dayOffs, dayOff)
+		}
+	}
+
+	// Overrides replace their occurrence even when moved out of the window
+	overrides, err := repo.FindAllByFathers(seriesIDs, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load day offs")
+	}
+	replaced := map[uuid.UUID][]time.Time{}
+	for _, override := range overrides {
+		if override.RecurrenceID != nil {
+			replaced[*override.DayOffFatherID] = append(replaced[*override.DayOffFatherID], *override.RecurrenceID)
+		}
+	}
+	for _, dayOff := range series {
+		dayOffs = append(dayOffs, expandSeries(dayOff, replaced[*dayOff.ID], from, to)...)
+	}
+
+	sort.SliceStable(dayOffs, func(a, b int) bool { return dayOffs[a].InitHour.Before(*dayOffs[b].InitHour) })
+	return dayOffs, nil
+}
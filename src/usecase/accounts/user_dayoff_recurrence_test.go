@@ -0,0 +1,289 @@
+package usecase_accounts
+
+import (
+	entity_accounts "app/entity/accounts"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLegacyRecurrence(t *testing.T) {
+	tests := []struct {
+		repeatType  string
+		repeatValue string
+		want        string
+		wantErr     bool
+	}{
+		{repeatType: "daily", repeatValue: "3", want: "FREQ=DAILY;COUNT=4"},
+		{repeatType: "yearly", repeatValue: "1", want: "FREQ=YEARLY;COUNT=2"},
+		{repeatType: "hourly", repeatValue: "3", wantErr: true},
+		{repeatType: "weekly", repeatValue: "0", wantErr: true},
+		{repeatType: "weekly", repeatValue: "many", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.repeatType+"/"+tt.repeatValue, func(t *testing.T) {
+			got, err := legacyRecurrence(tt.repeatType, tt.repeatValue)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRecurrence) {
+					t.Fatalf("legacyRecurrence() = %q, %v, want ErrInvalidRecurrence", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("legacyRecurrence() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("legacyRecurrence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	// Tuesday
+	start := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 10, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name    string
+		value   string
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			name:  "prefix and lowercase",
+			value: " rrule:freq=weekly;byday=tu,th;count=4 ",
+			want:  []time.Time{day(3), day(5), day(10), day(12)},
+		},
+		{
+			name:  "interval",
+			value: "FREQ=DAILY;INTERVAL=3;COUNT=3",
+			want:  []time.Time{day(3), day(6), day(9)},
+		},
+		{
+			name:  "until date includes the whole day",
+			value: "FREQ=DAILY;UNTIL=20260305",
+			want:  []time.Time{day(3), day(4), day(5)},
+		},
+		{
+			name:  "until time is exclusive of later starts",
+			value: "FREQ=DAILY;UNTIL=20260305T000000Z",
+			want:  []time.Time{day(3), day(4)},
+		},
+		{name: "dtstart in the rule", value: "DTSTART:20260303T100000Z\nFREQ=DAILY;COUNT=2", wantErr: true},
+		{name: "unknown frequency", value: "FREQ=SOMETIMES", wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := parseRecurrence(tt.value, start)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRecurrence) {
+					t.Fatalf("parseRecurrence(%q) error = %v, want ErrInvalidRecurrence", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRecurrence(%q) error = %v", tt.value, err)
+			}
+			if got := rule.All(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRecurrence(%q) occurrences = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRecurrence(t *testing.T) {
+	// Tuesday
+	start := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "forever", value: "FREQ=WEEKLY;BYDAY=TU"},
+		{name: "until", value: "FREQ=MONTHLY;UNTIL=20261231"},
+		{name: "hourly", value: "FREQ=HOURLY;COUNT=3", wantErr: true},
+		{name: "until before the start", value: "FREQ=DAILY;UNTIL=20260301", wantErr: true},
+		{name: "start is not an occurrence", value: "FREQ=WEEKLY;BYDAY=WE", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateRecurrence(tt.value, start)
+			if tt.wantErr && !errors.Is(err, ErrInvalidRecurrence) {
+				t.Errorf("validateRecurrence(%q) error = %v, want ErrInvalidRecurrence", tt.value, err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateRecurrence(%q) error = %v", tt.value, err)
+			}
+		})
+	}
+}
+
+func TestExpandSeries(t *testing.T) {
+	day := func(d int, hour int) time.Time { return time.Date(2026, 3, d, hour, 0, 0, 0, time.UTC) }
+	newSeries := func(rrule string, exDates ...time.Time) *entity_accounts.UserDayOff {
+		initHour, endHour := day(3, 10), day(3, 12)
+		return &entity_accounts.UserDayOff{InitHour: &initHour, EndHour: &endHour, RRule: rrule, ExDates: exDates, TimeZone: "UTC"}
+	}
+	fatherID := uuid.New()
+	override := newSeries("FREQ=DAILY")
+	override.DayOffFatherID = &fatherID
+
+	tests := []struct {
+		name     string
+		series   *entity_accounts.UserDayOff
+		replaced []time.Time
+		from     time.Time
+		to       time.Time
+		want     []time.Time // Starts of the occurrences
+	}{
+		{
+			name:   "whole series",
+			series: newSeries("FREQ=DAILY;COUNT=3"),
+			from:   day(1, 0),
+			to:     day(31, 0),
+			want:   []time.Time{day(3, 10), day(4, 10), day(5, 10)},
+		},
+		{
+			name:   "occurrences overlapping the window edges",
+			series: newSeries("FREQ=DAILY"),
+			from:   day(4, 11),
+			to:     day(6, 11),
+			want:   []time.Time{day(4, 10), day(5, 10), day(6, 10)},
+		},
+		{
+			name:   "window starting when an occurrence ends",
+			series: newSeries("FREQ=DAILY"),
+			from:   day(4, 12),
+			to:     day(5, 10),
+			want:   []time.Time{},
+		},
+		{
+			name:     "cancelled and replaced occurrences",
+			series:   newSeries("FREQ=DAILY;COUNT=5", day(4, 10)),
+			replaced: []time.Time{day(6, 10)},
+			from:     day(1, 0),
+			to:       day(31, 0),
+			want:     []time.Time{day(3, 10), day(5, 10), day(7, 10)},
+		},
+		{
+			name:   "weekly rule",
+			series: newSeries("FREQ=WEEKLY;BYDAY=TU,FR;UNTIL=20260313"),
+			from:   day(1, 0),
+			to:     day(31, 0),
+			want:   []time.Time{day(3, 10), day(6, 10), day(10, 10), day(13, 10)},
+		},
+		{
+			name:   "day off that does not repeat",
+			series: newSeries(""),
+			from:   day(1, 0),
+			to:     day(31, 0),
+		},
+		{
+			name:   "override",
+			series: override,
+			from:   day(1, 0),
+			to:     day(31, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences := expandSeries(tt.series, tt.replaced, tt.from, tt.to)
+			if tt.want == nil {
+				if occurrences != nil {
+					t.Fatalf("expandSeries() = %d occurrences, want none", len(occurrences))
+				}
+				return
+			}
+
+			starts := []time.Time{}
+			for _, occurrence := range occurrences {
+				starts = append(starts, *occurrence.InitHour)
+				if !occurrence.RecurrenceID.Equal(*occurrence.InitHour) {
+					t.Errorf("occurrence at %v has recurrence id %v", occurrence.InitHour, occurrence.RecurrenceID)
+				}
+				if duration := occurrence.EndHour.Sub(*occurrence.InitHour); duration != 2*time.Hour {
+					t.Errorf("occurrence at %v lasts %v, want 2h", occurrence.InitHour, duration)
+				}
+			}
+			if !reflect.DeepEqual(starts, tt.want) {
+				t.Errorf("expandSeries() starts = %v, want %v", starts, tt.want)
+			}
+			if !tt.series.InitHour.Equal(day(3, 10)) || tt.series.RecurrenceID != nil {
+				t.Errorf("expandSeries() changed the series")
+			}
+		})
+	}
+}
+
+func TestExpandSeriesAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	wallClock := func(d int) time.Time { return time.Date(2026, 3, d, 9, 0, 0, 0, newYork) }
+
+	// Stored in UTC as read from the database; DST starts on March 8
+	initHour, endHour := wallClock(5).UTC(), wallClock(5).Add(8*time.Hour).UTC()
+	exDate := wallClock(19).UTC()
+
+	tests := []struct {
+		name     string
+		timeZone string
+		want     []time.Time
+	}{
+		{
+			name:     "series time zone keeps the wall-clock time",
+			timeZone: "America/New_York",
+			want:     []time.Time{wallClock(5), wallClock(12), wallClock(26)},
+		},
+		{
+			name:     "utc series keeps the utc time",
+			timeZone: "UTC",
+			want:     []time.Time{wallClock(5), wallClock(12).Add(time.Hour), wallClock(19).Add(time.Hour), wallClock(26).Add(time.Hour)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := &entity_accounts.UserDayOff{
+				InitHour: &initHour,
+				EndHour:  &endHour,
+				RRule:    "FREQ=WEEKLY;COUNT=4",
+				ExDates:  []time.Time{exDate},
+				TimeZone: tt.timeZone,
+			}
+			occurrences := expandSeries(series, nil, wallClock(1), wallClock(31))
+
+			starts := []time.Time{}
+			for _, occurrence := range occurrences {
+				starts = append(starts, *occurrence.InitHour)
+				if duration := occurrence.EndHour.Sub(*occurrence.InitHour); duration != 8*time.Hour {
+					t.Errorf("occurrence at %v lasts %v, want 8h", occurrence.InitHour, duration)
+				}
+			}
+			if len(starts) != len(tt.want) {
+				t.Fatalf("expandSeries() starts = %v, want %v", starts, tt.want)
+			}
+			for i := range starts {
+				if !starts[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d starts at %v, want %v", i, starts[i], tt.want[i])
+				}
+			}
+			for _, start := range tt.want {
+				if !isOccurrence(series, start) {
+					t.Errorf("isOccurrence(%v) = false, want true", start)
+				}
+			}
+		})
+	}
+}
@@ -2,12 +2,12 @@ package usecase_accounts
 
 import (
 	entity_accounts "app/entity/accounts"
+	"errors"
 	"fmt"
-	"log"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/teambition/rrule-go"
 )
 
 const (
@@ -20,225 +20,459 @@ const (
 	DeleteModeAll    = "all"
 )
 
+var ErrDayOffNotFound = errors.New("day off not found")
+
 type userDayOffUseCase struct {
-	repo IRepositoryUserDayOff
+	repo        IRepositoryUserDayOff
+	transaction TransactionUserDayOff
+	repoUser    IRepositoryUser
+}
+
+func NewUserDayOffUseCase(repo IRepositoryUserDayOff, transaction TransactionUserDayOff, repoUser IRepositoryUser) IUseCaseUserDayOff {
+	return &userDayOffUseCase{repo: repo, transaction: transaction, repoUser: repoUser}
 }
 
-func NewUserDayOffUseCase(repo IRepositoryUserDayOff) IUseCaseUserDayOff {
-	return &userDayOffUseCase{repo: repo}
+// inTransaction runs fn with a use case whose repository writes in a single
+// transaction, so a series is never left half updated.
+func (u *userDayOffUseCase) inTransaction(fn func(tx *userDayOffUseCase) error) error {
+	return u.transaction(func(repo IRepositoryUserDayOff) error {
+		// Already in the transaction, so nested calls reuse it
+		inTx := func(fn func(repo IRepositoryUserDayOff) error) error { return fn(repo) }
+		return fn(&userDayOffUseCase{repo: repo, transaction: inTx, repoUser: u.repoUser})
+	})
+}
+
+// location returns the time zone named, or the owner's when the name is
+// empty.
+func (u *userDayOffUseCase) location(timeZone string, ownerID int) (*time.Location, error) {
+	if timeZone == "" {
+		user, err := u.repoUser.FindById(ownerID)
+		if err != nil {
+			return nil, fmt.Errorf("user not found")
+		}
+		timeZone = user.TimeZone
+	}
+	return loadTimeZone(timeZone)
 }
 
 func (u *userDayOffUseCase) Create(dayOff *entity_accounts.UserDayOff, ownerID int) error {
 	dayOff.OwnerID = ownerID
+	loc, err := u.location(dayOff.TimeZone, ownerID)
+	if err != nil {
+		return err
+	}
+	dayOff.TimeZone = loc.String()
+
+	// Series created with the legacy repeat fields are converted to an RRULE
+	if dayOff.RRule == "" && dayOff.Repeat && dayOff.RepeatValue != "" {
+		value, err := legacyRecurrence(dayOff.RepeatType, dayOff.RepeatValue)
+		if err != nil {
+			return err
+		}
+		dayOff.RRule = value
+	}
+
+	// A series is stored once and its occurrences are expanded on read
+	if dayOff.RRule != "" {
+		rule, err := validateRecurrence(dayOff.RRule, dayOff.InitHour.In(loc))
+		if err != nil {
+			return err
+		}
+		setRecurrence(dayOff, rule)
+	} else {
+		dayOff.Repeat = false
+		dayOff.RepeatType = ""
+		dayOff.RepeatValue = ""
+	}
 
-	// Create the primary day off
 	if err := u.repo.Create(dayOff); err != nil {
 		return fmt.Errorf("could not create day off")
 	}
+	return nil
+}
+
+// seriesOccurrence is the occurrence of a series an update or delete
+// applies to.
+type seriesOccurrence struct {
+	series       *entity_accounts.UserDayOff
+	overrides    []*entity_accounts.UserDayOff
+	override     *entity_accounts.UserDayOff // Stored occurrence, nil while it follows the rule
+	recurrenceID time.Time
+	start        time.Time
+	end          time.Time
+}
 
-	// Handle recurrence in goroutine if needed
-	if dayOff.Repeat && dayOff.RepeatValue != "" {
-		log.Printf("handle recurrence %s", dayOff.RepeatValue)
-		u.handleRecurrence(dayOff, ownerID)
+// findOccurrence returns the occurrence of a series addressed by a day off
+// id: an override, or the occurrence of the series starting at occurrence,
+// its first one by default.
+func (u *userDayOffUseCase) findOccurrence(existing *entity_accounts.UserDayOff, ownerID int, occurrence *time.Time) (*seriesOccurrence, error) {
+	series := existing
+	if existing.DayOffFatherID != nil {
+		father, err := u.repo.FindByIdAndOwner(*existing.DayOffFatherID, ownerID)
+		if err != nil {
+			return nil, ErrDayOffNotFound
+		}
+		series = father
+	}
+	overrides, err := u.repo.FindAllByFather(*series.ID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load day offs")
 	}
 
-	return nil
+	target := &seriesOccurrence{series: series, overrides: overrides, recurrenceID: *series.InitHour}
+	if existing != series {
+		target.override = existing
+		if existing.RecurrenceID != nil {
+			target.recurrenceID = *existing.RecurrenceID
+		}
+		target.start, target.end = *existing.InitHour, *existing.EndHour
+		return target, nil
+	}
+
+	if occurrence != nil {
+		target.recurrenceID = *occurrence
+	}
+	for _, override := range overrides {
+		if override.RecurrenceID != nil && override.RecurrenceID.Equal(target.recurrenceID) {
+			target.override = override
+			target.start, target.end = *override.InitHour, *override.EndHour
+			return target, nil
+		}
+	}
+	if !isOccurrence(series, target.recurrenceID) {
+		return nil, ErrOccurrenceNotFound
+	}
+	target.start = target.recurrenceID
+	target.end = target.recurrenceID.Add(series.EndHour.Sub(*series.InitHour))
+	return target, nil
+}
+
+// wallShift moves instants by whole days and a time of day in wall-clock
+// time, the way the occurrences of a series move when it is expanded in its
+// time zone, so they keep agreeing with the rule across DST changes.
+type wallShift struct {
+	from, to *time.Location
+	days     int
+	clock    time.Duration
 }
 
-func (u *userDayOffUseCase) handleRecurrence(father *entity_accounts.UserDayOff, ownerID int) {
-	// Simple recurrence logic: create for next 30 occurrences ? or based on RepeatValue?
-	// User said "create in mass". Let's assume a fixed number for now or parse RepeatValue.
-	// Since `RepeatValue` format isn't strictly defined, I'll assume it's like "10" for 10 times, or maybe just create a reasonable amount (e.g., 50) for now until specced.
-	// Actually, let's create for 1 year ahead or 50 occurrences max to avoid infinite loop.
-
-	currentStart := *father.InitHour
-	currentEnd := *father.EndHour
-	max, _ := strconv.Atoi(father.RepeatValue)
-
-RecurrenceLoop:
-	for i := 0; i < max; i++ {
-		// Calculate next date
-		log.Printf("recurrence %d\n Current start: %v, Current end: %v", i, currentStart, currentEnd)
-		switch father.RepeatType {
-		case entity_accounts.RepeatTypeWeekly:
-			log.Printf("weekly recurrence %d", i)
-			currentStart = currentStart.AddDate(0, 0, 7)
-			currentEnd = currentEnd.AddDate(0, 0, 7)
-		case entity_accounts.RepeatTypeDaily:
-			log.Printf("daily recurrence %d", i)
-			currentStart = currentStart.AddDate(0, 0, 1)
-			currentEnd = currentEnd.AddDate(0, 0, 1)
-		case entity_accounts.RepeatTypeMonthly:
-			log.Printf("monthly recurrence %d", i)
-			currentStart = currentStart.AddDate(0, 1, 0)
-			currentEnd = currentEnd.AddDate(0, 1, 0)
-		case entity_accounts.RepeatTypeYearly:
-			log.Printf("yearly recurrence %d", i)
-			currentStart = currentStart.AddDate(1, 0, 0)
-			currentEnd = currentEnd.AddDate(1, 0, 0)
-		default:
-			log.Printf("unknown recurrence type %d", i)
-			break RecurrenceLoop
-		}
-
-		id := uuid.New()
-		newDayOff := &entity_accounts.UserDayOff{
-			ID:             &id,
-			InitHour:       &currentStart,
-			EndHour:        &currentEnd,
-			OwnerID:        ownerID,
-			Repeat:         true, // They are part of a repeating series
-			RepeatType:     father.RepeatType,
-			RepeatValue:    father.RepeatValue,
-			DayOffFatherID: father.ID, // Link to father
-			CreatedAt:      time.Now(),
-			UpdatedAt:      time.Now(),
-		}
-		_ = u.repo.Create(newDayOff)
+// newWallShift returns the shift that moves from, read in the location
+// fromLoc, to to in the location toLoc.
+func newWallShift(from time.Time, fromLoc *time.Location, to time.Time, toLoc *time.Location) wallShift {
+	from, to = from.In(fromLoc), to.In(toLoc)
+	fromYear, fromMonth, fromDay := from.Date()
+	toYear, toMonth, toDay := to.Date()
+	days := time.Date(toYear, toMonth, toDay, 0, 0, 0, 0, time.UTC).Sub(time.Date(fromYear, fromMonth, fromDay, 0, 0, 0, 0, time.UTC))
+	return wallShift{
+		from:  fromLoc,
+		to:    toLoc,
+		days:  int(days / (24 * time.Hour)),
+		clock: timeOfDay(to) - timeOfDay(from),
 	}
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	hour, minute, second := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute + time.Duration(second)*time.Second + time.Duration(t.Nanosecond())
+}
 
+func (s wallShift) apply(t time.Time) time.Time {
+	t = t.In(s.from)
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).AddDate(0, 0, s.days)
+	wall := midnight.Add(timeOfDay(t) + s.clock)
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), s.to)
 }
 
-func (u *userDayOffUseCase) Update(dayOff *entity_accounts.UserDayOff, ownerID int, mode string) error {
+func (s wallShift) shift(t *time.Time) *time.Time {
+	shifted := s.apply(*t)
+	return &shifted
+}
+
+// splitRule ends a rule before at, and returns the options of a rule for
+// the occurrences from at on.
+func splitRule(rule *rrule.RRule, at time.Time) (rrule.ROption, rrule.ROption) {
+	before, after := rule.OrigOptions, rule.OrigOptions
+	if before.Count > 0 {
+		count := len(rule.Between(before.Dtstart.Add(-time.Second), at, false))
+		before.Count, after.Count = count, before.Count-count
+	} else {
+		before.Until = at.Add(-time.Second)
+	}
+	after.Dtstart = at
+	return before, after
+}
+
+func (u *userDayOffUseCase) Update(dayOff *entity_accounts.UserDayOff, ownerID int, mode string, occurrence *time.Time) error {
 	if dayOff.ID == nil {
 		return fmt.Errorf("id required")
 	}
+	return u.inTransaction(func(tx *userDayOffUseCase) error {
+		return tx.update(dayOff, ownerID, mode, occurrence)
+	})
+}
 
+func (u *userDayOffUseCase) update(dayOff *entity_accounts.UserDayOff, ownerID int, mode string, occurrence *time.Time) error {
 	existing, err := u.repo.FindByIdAndOwner(*dayOff.ID, ownerID)
 	if err != nil {
-		return fmt.Errorf("day off not found")
+		return ErrDayOffNotFound
+	}
+	loc := seriesLocation(existing)
+	if dayOff.TimeZone != "" {
+		if loc, err = loadTimeZone(dayOff.TimeZone); err != nil {
+			return err
+		}
 	}
 
-	// Prepare update function
-	updateFields := func(target *entity_accounts.UserDayOff) {
-		// Calculate duration difference to shift start/end times correctly?
-		// Or just replace times?
-		// User request: "updates all or from date selected".
-		// Usually this means shifting time or changing properties.
-		// For simplicity, let's assume we copy properties but keep the DATE part of the target, only updating TIME part?
-		// Or if Recurrence changes... that's complex.
-		// Let's assume checking if title/desc changed (UserDayOff doesn't have title).
-		// We only have InitHour/EndHour.
-		// If we change InitHour of one, we usually want to shift others by same delta.
-
-		// TODO: Implement sophisticated delta shifting.
-		// For now, I will just update the target's fields directly if it's SINGLE.
-		// For FUTURE/ALL, doing a straight copy of InitHour would make them all on the SAME DAY, which is wrong.
-		// So we MUST calculate the Delta.
-
-		deltaStart := dayOff.InitHour.Sub(*existing.InitHour)
-		deltaEnd := dayOff.EndHour.Sub(*existing.EndHour)
+	if existing.RRule == "" && existing.DayOffFatherID == nil {
+		// A day off that does not repeat, unless it is given a rule
+		existing.InitHour, existing.EndHour = dayOff.InitHour, dayOff.EndHour
+		existing.TimeZone = loc.String()
+		if dayOff.RRule != "" {
+			rule, err := validateRecurrence(dayOff.RRule, existing.InitHour.In(loc))
+			if err != nil {
+				return err
+			}
+			setRecurrence(existing, rule)
+		}
+		if err := u.repo.Update(existing); err != nil {
+			return fmt.Errorf("could not update day off")
+		}
+		return nil
+	}
 
-		newStart := target.InitHour.Add(deltaStart)
-		newEnd := target.EndHour.Add(deltaEnd)
+	target, err := u.findOccurrence(existing, ownerID, occurrence)
+	if err != nil {
+		return err
+	}
 
-		target.InitHour = &newStart
-		target.EndHour = &newEnd
-		target.UpdatedAt = time.Now()
+	switch mode {
+	case UpdateModeSingle:
+		if dayOff.RRule != "" || dayOff.TimeZone != "" {
+			return fmt.Errorf("%w: rrule and time_zone can only be changed with mode all or future", ErrInvalidRecurrence)
+		}
+		// The occurrence is stored as an override of the series
+		override := target.override
+		if override == nil {
+			recurrenceID := target.recurrenceID
+			override = &entity_accounts.UserDayOff{OwnerID: ownerID, DayOffFatherID: target.series.ID, RecurrenceID: &recurrenceID, TimeZone: target.series.TimeZone}
+		}
+		override.InitHour, override.EndHour = dayOff.InitHour, dayOff.EndHour
+		if override.ID == nil {
+			err = u.repo.Create(override)
+		} else {
+			err = u.repo.Update(override)
+		}
+		if err != nil {
+			return fmt.Errorf("could not update day off")
+		}
+		return nil
+	case UpdateModeFuture, UpdateModeAll:
+	default:
+		return fmt.Errorf("invalid mode: must be single, future or all")
 	}
 
-	targets := []*entity_accounts.UserDayOff{}
+	// Later occurrences move as much as the one updated, and so do their
+	// exceptions. A new rule or time zone regenerates the occurrences; the
+	// exceptions are kept as they are
+	series := target.series
+	if dayOff.TimeZone == "" {
+		loc = seriesLocation(series)
+	}
+	shiftStart := newWallShift(target.start, seriesLocation(series), *dayOff.InitHour, loc)
+	shiftEnd := newWallShift(target.end, seriesLocation(series), *dayOff.EndHour, loc)
+	rule := seriesRule(series)
+	if rule == nil {
+		return fmt.Errorf("%w: the series has an invalid rule", ErrInvalidRecurrence)
+	}
 
-	if mode == UpdateModeSingle {
-		targets = append(targets, existing)
-	} else {
-		// We need the FatherID. If existing is Father, use its ID. If not, use existing.DayOffFatherID.
-		fatherID := existing.ID
-		if existing.DayOffFatherID != nil {
-			fatherID = existing.DayOffFatherID
-		}
-
-		if mode == UpdateModeAll {
-			// Find ALL by FatherID (including father itself)
-			// My repo `FindAllByFather` finds children. Does it find father? Usually no.
-			// We need a way to find ALL in series.
-			// Strategy: Find Father + Find Children.
-			// Re-use logic: fetch all where ID=FatherID OR FatherID=FatherID.
-			// Implementation detail: Use repo to find siblings.
-
-			// Simplification: We need improved repo support or just 2 queries.
-			// Current repo `FindAllByFather` finds where `day_off_father_id = ?`.
-			// So we also need to fetch the father.
-			father, _ := u.repo.FindByIdAndOwner(*fatherID, ownerID)
-			if father != nil {
-				targets = append(targets, father)
+	if mode == UpdateModeAll || !target.recurrenceID.After(*series.InitHour) {
+		option := rule.OrigOptions
+		if !option.Until.IsZero() {
+			option.Until = shiftStart.apply(option.Until)
+		}
+		value := option.RRuleString()
+		if dayOff.RRule != "" {
+			value = dayOff.RRule
+		}
+		newStart := shiftStart.apply(*series.InitHour)
+		shifted, err := validateRecurrence(value, newStart)
+		if err != nil {
+			return err
+		}
+		series.InitHour = &newStart
+		series.TimeZone = loc.String()
+		series.EndHour = shiftEnd.shift(series.EndHour)
+		for i := range series.ExDates {
+			series.ExDates[i] = shiftStart.apply(series.ExDates[i])
+		}
+		setRecurrence(series, shifted)
+		if err := u.repo.Update(series); err != nil {
+			return fmt.Errorf("could not update day off")
+		}
+		for _, override := range target.overrides {
+			override.InitHour = shiftStart.shift(override.InitHour)
+			override.EndHour = shiftEnd.shift(override.EndHour)
+			if override.RecurrenceID != nil {
+				override.RecurrenceID = shiftStart.shift(override.RecurrenceID)
 			}
-			children, _ := u.repo.FindAllByFather(*fatherID, ownerID)
-			targets = append(targets, children...)
-
-		} else if mode == UpdateModeFuture {
-			// Find children where init_hour >= existing.InitHour
-			futureChildren, _ := u.repo.FindFutureByName(*fatherID, *existing.InitHour, ownerID)
-
-			// Also include `existing` itself since it's "from date selected" (inclusive usually).
-			// `FindFutureByName` uses `>=` so it might include existing if times match exactly or valid logic.
-			// Safe bet: Add `existing` if not covered, but repository `FindFuture` might cover it if criteria matches.
-			// Actually `FindFutureByName` searches children. If `existing` is child, it's covered.
-			// If `existing` is Father, we need to add it.
-			if existing.ID == fatherID { // It is the father
-				targets = append(targets, existing)
+			override.TimeZone = series.TimeZone
+			if err := u.repo.Update(override); err != nil {
+				return fmt.Errorf("could not update day off")
 			}
-			targets = append(targets, futureChildren...)
 		}
+		return nil
 	}
 
-	// Apply updates
-	for _, target := range targets {
-		updateFields(target)
-		u.repo.Update(target)
+	// The series ends before the occurrence and a new one starts with the
+	// first occurrence the rule generates from there
+	first := rule.After(target.recurrenceID, true)
+	if !first.IsZero() {
+		before, after := splitRule(rule, first)
+		if !after.Until.IsZero() {
+			after.Until = shiftStart.apply(after.Until)
+		}
+		value := after.RRuleString()
+		if dayOff.RRule != "" {
+			value = dayOff.RRule
+		}
+		newStart := shiftStart.apply(first)
+		newEnd := shiftEnd.apply(first.Add(series.EndHour.Sub(*series.InitHour)))
+		afterRule, err := validateRecurrence(value, newStart)
+		if err != nil {
+			return err
+		}
+		beforeRule, err := rrule.NewRRule(before)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+
+		next := &entity_accounts.UserDayOff{InitHour: &newStart, EndHour: &newEnd, OwnerID: ownerID, ExDates: []time.Time{}, TimeZone: loc.String()}
+		kept := []time.Time{}
+		for _, exDate := range series.ExDates {
+			if exDate.Before(first) {
+				kept = append(kept, exDate)
+			} else {
+				next.ExDates = append(next.ExDates, shiftStart.apply(exDate))
+			}
+		}
+		series.ExDates = kept
+		setRecurrence(series, beforeRule)
+		setRecurrence(next, afterRule)
+		if err := u.repo.Create(next); err != nil {
+			return fmt.Errorf("could not update day off")
+		}
+		if err := u.repo.Update(series); err != nil {
+			return fmt.Errorf("could not update day off")
+		}
+		series = next
 	}
 
+	for _, override := range target.overrides {
+		if override.RecurrenceID == nil || override.RecurrenceID.Before(target.recurrenceID) {
+			continue
+		}
+		override.DayOffFatherID = series.ID
+		override.InitHour = shiftStart.shift(override.InitHour)
+		override.EndHour = shiftEnd.shift(override.EndHour)
+		override.RecurrenceID = shiftStart.shift(override.RecurrenceID)
+		override.TimeZone = series.TimeZone
+		if err := u.repo.Update(override); err != nil {
+			return fmt.Errorf("could not update day off")
+		}
+	}
 	return nil
 }
 
-func (u *userDayOffUseCase) Delete(id uuid.UUID, ownerID int, mode string) error {
+func (u *userDayOffUseCase) Delete(id uuid.UUID, ownerID int, mode string, occurrence *time.Time) error {
+	return u.inTransaction(func(tx *userDayOffUseCase) error {
+		return tx.delete(id, ownerID, mode, occurrence)
+	})
+}
+
+func (u *userDayOffUseCase) delete(id uuid.UUID, ownerID int, mode string, occurrence *time.Time) error {
 	existing, err := u.repo.FindByIdAndOwner(id, ownerID)
 	if err != nil {
-		return fmt.Errorf("day off not found")
+		return ErrDayOffNotFound
 	}
-
-	idsToDelete := []uuid.UUID{}
-
-	if mode == DeleteModeSingle {
-		idsToDelete = append(idsToDelete, id)
-	} else {
-		fatherID := existing.ID
-		if existing.DayOffFatherID != nil {
-			fatherID = existing.DayOffFatherID
+	if existing.RRule == "" && existing.DayOffFatherID == nil {
+		if err := u.repo.DeleteById(id); err != nil {
+			return fmt.Errorf("could not delete day off")
 		}
+		return nil
+	}
 
-		if mode == DeleteModeAll {
-			idsToDelete = append(idsToDelete, *fatherID)
-			children, _ := u.repo.FindAllByFather(*fatherID, ownerID)
-			for _, c := range children {
-				idsToDelete = append(idsToDelete, *c.ID)
+	target, err := u.findOccurrence(existing, ownerID, occurrence)
+	if err != nil {
+		return err
+	}
+	series := target.series
+
+	switch mode {
+	case DeleteModeSingle:
+		// The occurrence is cancelled so the rule does not generate it again
+		if target.override != nil {
+			if err := u.repo.DeleteById(*target.override.ID); err != nil {
+				return fmt.Errorf("could not delete day off")
 			}
-		} else if mode == DeleteModeFuture {
-			// Future includes existing + subsequent
-			if existing.ID == fatherID {
-				idsToDelete = append(idsToDelete, *existing.ID)
+		}
+		if isOccurrence(series, target.recurrenceID) {
+			series.ExDates = append(series.ExDates, target.recurrenceID)
+			if err := u.repo.Update(series); err != nil {
+				return fmt.Errorf("could not delete day off")
 			}
-			children, _ := u.repo.FindFutureByName(*fatherID, *existing.InitHour, ownerID)
-			for _, c := range children {
-				idsToDelete = append(idsToDelete, *c.ID)
+		}
+		return nil
+	case DeleteModeFuture:
+		if !target.recurrenceID.After(*series.InitHour) {
+			break
+		}
+
+		rule := seriesRule(series)
+		if rule == nil {
+			return fmt.Errorf("%w: the series has an invalid rule", ErrInvalidRecurrence)
+		}
+		before, _ := splitRule(rule, target.recurrenceID)
+		beforeRule, err := rrule.NewRRule(before)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+		kept := []time.Time{}
+		for _, exDate := range series.ExDates {
+			if exDate.Before(target.recurrenceID) {
+				kept = append(kept, exDate)
 			}
-			// If existing is a child, FindFutureByName should have found it (check repo logic).
-			// If not, ensure existing is added.
-			found := false
-			for _, tid := range idsToDelete {
-				if tid == *existing.ID {
-					found = true
-					break
-				}
+		}
+		series.ExDates = kept
+		setRecurrence(series, beforeRule)
+		if err := u.repo.Update(series); err != nil {
+			return fmt.Errorf("could not delete day off")
+		}
+
+		idsToDelete := []uuid.UUID{}
+		for _, override := range target.overrides {
+			if override.RecurrenceID != nil && !override.RecurrenceID.Before(target.recurrenceID) {
+				idsToDelete = append(idsToDelete, *override.ID)
 			}
-			if !found {
-				idsToDelete = append(idsToDelete, *existing.ID)
+		}
+		if len(idsToDelete) > 0 {
+			if err := u.repo.DeleteBatch(idsToDelete); err != nil {
+				return fmt.Errorf("could not delete day off")
 			}
 		}
+		return nil
+	case DeleteModeAll:
+	default:
+		return fmt.Errorf("invalid mode: must be single, future or all")
 	}
 
-	if len(idsToDelete) > 0 {
-		return u.repo.DeleteBatch(idsToDelete)
+	// The whole series goes, with its overrides
+	idsToDelete := []uuid.UUID{*series.ID}
+	for _, override := range target.overrides {
+		idsToDelete = append(idsToDelete, *override.ID)
+	}
+	if err := u.repo.DeleteBatch(idsToDelete); err != nil {
+		return fmt.Errorf("could not delete day off")
 	}
 	return nil
 }
@@ -247,12 +481,20 @@ func (u *userDayOffUseCase) GetById(id uuid.UUID, ownerID int) (*entity_accounts
 	return u.repo.FindByIdAndOwner(id, ownerID)
 }
 
-func (u *userDayOffUseCase) GetAll(ownerID int, filterType string, year, week, month int) ([]*entity_accounts.UserDayOff, error) {
-	// If no filter is specified, return all day-offs
+func (u *userDayOffUseCase) GetAll(ownerID int, filterType string, year, week, month int, timeZone string) ([]*entity_accounts.UserDayOff, error) {
+	// If no filter is specified, return all day-offs as stored: each series
+	// once, with the overrides of its occurrences
 	if filterType == "" {
 		return u.repo.FindAllByOwner(ownerID)
 	}
 
+	// Periods start at midnight in the requested time zone, the owner's by
+	// default
+	loc, err := u.location(timeZone, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Calculate date range based on filter type
 	var startDate, endDate time.Time
 
@@ -263,7 +505,7 @@ func (u *userDayOffUseCase) GetAll(ownerID int, filterType string, year, week, m
 		}
 		// Calculate the first day of the week (Monday)
 		// ISO 8601 week starts on Monday
-		firstDayOfYear := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		firstDayOfYear := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
 		// Find the first Monday of the year
 		daysUntilMonday := (8 - int(firstDayOfYear.Weekday())) % 7
 		if firstDayOfYear.Weekday() == time.Sunday {
@@ -282,19 +524,19 @@ func (u *userDayOffUseCase) GetAll(ownerID int, filterType string, year, week, m
 		if year == 0 || month == 0 || month > 12 {
 			return nil, fmt.Errorf("invalid month filter: year and month (1-12) required")
 		}
-		startDate = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		startDate = time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
 		endDate = startDate.AddDate(0, 1, 0)
 
 	case "year":
 		if year == 0 {
 			return nil, fmt.Errorf("invalid year filter: year required")
 		}
-		startDate = time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		startDate = time.Date(year, 1, 1, 0, 0, 0, 0, loc)
 		endDate = startDate.AddDate(1, 0, 0)
 
 	default:
 		return nil, fmt.Errorf("invalid filter_type: must be 'week', 'month', or 'year'")
 	}
 
-	return u.repo.FindAllByOwnerWithFilter(ownerID, &startDate, &endDate)
+	return DayOffsBetween(u.repo, ownerID, startDate, endDate)
 }
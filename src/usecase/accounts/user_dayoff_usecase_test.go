@@ -0,0 +1,647 @@
+package usecase_accounts
+
+import (
+	entity_accounts "app/entity/accounts"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeDayOffRepository keeps day offs in memory. It stores and returns
+// copies, as the database does, and its transaction restores the day offs
+// when fn fails.
+type fakeDayOffRepository struct {
+	dayOffs   map[uuid.UUID]*entity_accounts.UserDayOff
+	writes    int
+	failWrite int // Write that fails, counting from 1; 0 never fails
+}
+
+func newFakeDayOffRepository(dayOffs ...*entity_accounts.UserDayOff) *fakeDayOffRepository {
+	r := &fakeDayOffRepository{dayOffs: map[uuid.UUID]*entity_accounts.UserDayOff{}}
+	for _, dayOff := range dayOffs {
+		r.dayOffs[*dayOff.ID] = copyDayOff(dayOff)
+	}
+	return r
+}
+
+func copyDayOff(dayOff *entity_accounts.UserDayOff) *entity_accounts.UserDayOff {
+	copied := *dayOff
+	copied.ExDates = append([]time.Time{}, dayOff.ExDates...)
+	return &copied
+}
+
+func (r *fakeDayOffRepository) transaction(fn func(repo IRepositoryUserDayOff) error) error {
+	snapshot := map[uuid.UUID]*entity_accounts.UserDayOff{}
+	for id, dayOff := range r.dayOffs {
+		snapshot[id] = copyDayOff(dayOff)
+	}
+	if err := fn(r); err != nil {
+		r.dayOffs = snapshot
+		return err
+	}
+	return nil
+}
+
+func (r *fakeDayOffRepository) write() error {
+	r.writes++
+	if r.writes == r.failWrite {
+		return errors.New("write failed")
+	}
+	return nil
+}
+
+func (r *fakeDayOffRepository) find(match func(dayOff *entity_accounts.UserDayOff) bool) []*entity_accounts.UserDayOff {
+	dayOffs := []*entity_accounts.UserDayOff{}
+	for _, dayOff := range r.dayOffs {
+		if match(dayOff) {
+			dayOffs = append(dayOffs, copyDayOff(dayOff))
+		}
+	}
+	return dayOffs
+}
+
+func (r *fakeDayOffRepository) Create(dayOff *entity_accounts.UserDayOff) error {
+	if err := r.write(); err != nil {
+		return err
+	}
+	id := uuid.New()
+	dayOff.ID = &id
+	r.dayOffs[id] = copyDayOff(dayOff)
+	return nil
+}
+
+func (r *fakeDayOffRepository) FindByIdAndOwner(id uuid.UUID, ownerID int) (*entity_accounts.UserDayOff, error) {
+	dayOff, ok := r.dayOffs[id]
+	if !ok || dayOff.OwnerID != ownerID {
+		return nil, errors.New("record not found")
+	}
+	return copyDayOff(dayOff), nil
+}
+
+func (r *fakeDayOffRepository) FindAllByOwner(ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.find(func(dayOff *entity_accounts.UserDayOff) bool { return dayOff.OwnerID == ownerID }), nil
+}
+
+func (r *fakeDayOffRepository) FindAllByOwnerWithFilter(ownerID int, startDate, endDate *time.Time) ([]*entity_accounts.UserDayOff, error) {
+	return r.FindAllByOwner(ownerID)
+}
+
+func (r *fakeDayOffRepository) FindAllByFather(fatherID uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.FindAllByFathers([]uuid.UUID{fatherID}, ownerID)
+}
+
+func (r *fakeDayOffRepository) FindAllByFathers(fatherIDs []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.find(func(dayOff *entity_accounts.UserDayOff) bool {
+		if dayOff.OwnerID != ownerID || dayOff.DayOffFatherID == nil {
+			return false
+		}
+		for _, id := range fatherIDs {
+			if *dayOff.DayOffFatherID == id {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func (r *fakeDayOffRepository) FindAllByIdsAndOwner(ids []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.find(func(dayOff *entity_accounts.UserDayOff) bool {
+		for _, id := range ids {
+			if *dayOff.ID == id {
+				return dayOff.OwnerID == ownerID
+			}
+		}
+		return false
+	}), nil
+}
+
+func (r *fakeDayOffRepository) FindAllByICalUIDs(uids []string, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.find(func(dayOff *entity_accounts.UserDayOff) bool {
+		for _, uid := range uids {
+			if dayOff.ICalUID != nil && *dayOff.ICalUID == uid {
+				return dayOff.OwnerID == ownerID
+			}
+		}
+		return false
+	}), nil
+}
+
+func (r *fakeDayOffRepository) DeleteById(id uuid.UUID) error {
+	if err := r.write(); err != nil {
+		return err
+	}
+	delete(r.dayOffs, id)
+	return nil
+}
+
+func (r *fakeDayOffRepository) DeleteBatch(ids []uuid.UUID) error {
+	if err := r.write(); err != nil {
+		return err
+	}
+	for _, id := range ids {
+		delete(r.dayOffs, id)
+	}
+	return nil
+}
+
+func (r *fakeDayOffRepository) Update(dayOff *entity_accounts.UserDayOff) error {
+	if err := r.write(); err != nil {
+		return err
+	}
+	r.dayOffs[*dayOff.ID] = copyDayOff(dayOff)
+	return nil
+}
+
+// newTestSeries returns a series of one hour day offs repeating by rule from
+// start, stored in UTC as read from the database.
+func newTestSeries(start time.Time, rule string, exDates ...time.Time) *entity_accounts.UserDayOff {
+	id := uuid.New()
+	initHour, endHour := start.UTC(), start.Add(time.Hour).UTC()
+	series := &entity_accounts.UserDayOff{ID: &id, InitHour: &initHour, EndHour: &endHour, OwnerID: 1, ExDates: []time.Time{}, TimeZone: start.Location().String()}
+	for _, exDate := range exDates {
+		series.ExDates = append(series.ExDates, exDate.UTC())
+	}
+	rrule, err := validateRecurrence(rule, start)
+	if err != nil {
+		panic(err)
+	}
+	setRecurrence(series, rrule)
+	return series
+}
+
+func newTestOverride(series *entity_accounts.UserDayOff, recurrenceID, start time.Time) *entity_accounts.UserDayOff {
+	id := uuid.New()
+	recurrenceID, initHour, endHour := recurrenceID.UTC(), start.UTC(), start.Add(time.Hour).UTC()
+	return &entity_accounts.UserDayOff{ID: &id, InitHour: &initHour, EndHour: &endHour, OwnerID: series.OwnerID, DayOffFatherID: series.ID, RecurrenceID: &recurrenceID, TimeZone: series.TimeZone}
+}
+
+func TestUpdateDayOffSeriesAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	// Mondays at 09:00; DST starts on March 29
+	monday := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, berlin)
+	}
+	secondOccurrence := monday(3, 16, 9).UTC()
+
+	tests := []struct {
+		name              string
+		mode              string
+		occurrence        *time.Time
+		newStart          time.Time
+		wantStart         time.Time
+		wantExDates       []time.Time
+		wantRecurrenceID  time.Time
+		wantOverrideStart time.Time
+	}{
+		{
+			name:              "all",
+			mode:              UpdateModeAll,
+			newStart:          monday(3, 16, 9),
+			wantStart:         monday(3, 16, 9),
+			wantExDates:       []time.Time{monday(3, 30, 9)},
+			wantRecurrenceID:  monday(4, 6, 9),
+			wantOverrideStart: monday(4, 6, 11),
+		},
+		{
+			name:              "future",
+			mode:              UpdateModeFuture,
+			occurrence:        &secondOccurrence,
+			newStart:          monday(3, 23, 9),
+			wantStart:         monday(3, 23, 9),
+			wantExDates:       []time.Time{monday(3, 30, 9)},
+			wantRecurrenceID:  monday(4, 6, 9),
+			wantOverrideStart: monday(4, 6, 11),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := newTestSeries(monday(3, 9, 9), "FREQ=WEEKLY;COUNT=8", monday(3, 23, 9))
+			override := newTestOverride(series, monday(3, 30, 9), monday(3, 30, 11))
+			repo := newFakeDayOffRepository(series, override)
+			usecase := NewUserDayOffUseCase(repo, repo.transaction, nil)
+
+			newStart, newEnd := tt.newStart.UTC(), tt.newStart.Add(time.Hour).UTC()
+			dayOff := &entity_accounts.UserDayOff{ID: series.ID, InitHour: &newStart, EndHour: &newEnd}
+			if err := usecase.Update(dayOff, 1, tt.mode, tt.occurrence); err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			stored := repo.dayOffs[*override.ID]
+			moved, err := repo.FindByIdAndOwner(*stored.DayOffFatherID, 1)
+			if err != nil {
+				t.Fatalf("series of the override not found: %v", err)
+			}
+			if !moved.InitHour.Equal(tt.wantStart) {
+				t.Errorf("series starts at %v, want %v", moved.InitHour.In(berlin), tt.wantStart)
+			}
+			if len(moved.ExDates) != len(tt.wantExDates) {
+				t.Fatalf("series exdates = %v, want %v", moved.ExDates, tt.wantExDates)
+			}
+			for i, exDate := range moved.ExDates {
+				if !exDate.Equal(tt.wantExDates[i]) {
+					t.Errorf("exdate %d = %v, want %v", i, exDate.In(berlin), tt.wantExDates[i])
+				}
+				// The rule must still generate the cancelled occurrence
+				if rule := seriesRule(moved); !rule.After(exDate, true).Equal(exDate) {
+					t.Errorf("exdate %v is not an occurrence of the series", exDate.In(berlin))
+				}
+			}
+			if !stored.RecurrenceID.Equal(tt.wantRecurrenceID) {
+				t.Errorf("override recurrence id = %v, want %v", stored.RecurrenceID.In(berlin), tt.wantRecurrenceID)
+			}
+			if !isOccurrence(moved, *stored.RecurrenceID) {
+				t.Errorf("override recurrence id %v is not an occurrence of the series", stored.RecurrenceID.In(berlin))
+			}
+			if !stored.InitHour.Equal(tt.wantOverrideStart) {
+				t.Errorf("override starts at %v, want %v", stored.InitHour.In(berlin), tt.wantOverrideStart)
+			}
+		})
+	}
+}
+
+// seriesFixture is a weekly series of six Mondays at 09:00 UTC from January
+// 5 2026. The occurrence of January 19 is cancelled and the one of January
+// 26 moved to 11:00.
+func seriesFixture() (series, override *entity_accounts.UserDayOff) {
+	series = newTestSeries(january(5, 9), "FREQ=WEEKLY;COUNT=6", january(19, 9))
+	override = newTestOverride(series, january(26, 9), january(26, 11))
+	return series, override
+}
+
+func january(day, hour int) time.Time {
+	return time.Date(2026, time.January, day, hour, 0, 0, 0, time.UTC)
+}
+
+// calendarOf returns the starts of the day offs the owner sees, with the
+// series expanded.
+func calendarOf(t *testing.T, repo IRepositoryUserDayOff) []string {
+	t.Helper()
+	dayOffs, err := DayOffsBetween(repo, 1, january(1, 0), january(1, 0).AddDate(1, 0, 0))
+	if err != nil {
+		t.Fatalf("DayOffsBetween() error = %v", err)
+	}
+	sort.Slice(dayOffs, func(i, j int) bool { return dayOffs[i].InitHour.Before(*dayOffs[j].InitHour) })
+	starts := []string{}
+	for _, dayOff := range dayOffs {
+		starts = append(starts, dayOff.InitHour.UTC().Format("Jan 2 15:04"))
+	}
+	return starts
+}
+
+func TestUpdateDayOffSeries(t *testing.T) {
+	series, override := seriesFixture()
+	occurrence := func(day int) *time.Time {
+		start := january(day, 9)
+		return &start
+	}
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		mode       string
+		occurrence *time.Time
+		newStart   time.Time
+		rrule      string
+		want       []string
+		wantSeries int
+		wantErr    error
+	}{
+		{
+			name:       "single occurrence becomes an override",
+			id:         *series.ID,
+			mode:       UpdateModeSingle,
+			occurrence: occurrence(12),
+			newStart:   time.Date(2026, time.January, 13, 10, 0, 0, 0, time.UTC),
+			want:       []string{"Jan 5 09:00", "Jan 13 10:00", "Jan 26 11:00", "Feb 2 09:00", "Feb 9 09:00"},
+			wantSeries: 1,
+		},
+		{
+			name:       "single override is updated in place",
+			id:         *override.ID,
+			mode:       UpdateModeSingle,
+			newStart:   time.Date(2026, time.January, 27, 8, 0, 0, 0, time.UTC),
+			want:       []string{"Jan 5 09:00", "Jan 12 09:00", "Jan 27 08:00", "Feb 2 09:00", "Feb 9 09:00"},
+			wantSeries: 1,
+		},
+		{
+			name:       "future splits the series and moves later exceptions",
+			id:         *series.ID,
+			mode:       UpdateModeFuture,
+			occurrence: occurrence(12),
+			newStart:   january(12, 10),
+			want:       []string{"Jan 5 09:00", "Jan 12 10:00", "Jan 26 12:00", "Feb 2 10:00", "Feb 9 10:00"},
+			wantSeries: 2,
+		},
+		{
+			name:       "future from the first occurrence moves the whole series",
+			id:         *series.ID,
+			mode:       UpdateModeFuture,
+			newStart:   january(5, 10),
+			want:       []string{"Jan 5 10:00", "Jan 12 10:00", "Jan 26 12:00", "Feb 2 10:00", "Feb 9 10:00"},
+			wantSeries: 1,
+		},
+		{
+			name:       "all moves the series with its exceptions",
+			id:         *series.ID,
+			mode:       UpdateModeAll,
+			newStart:   time.Date(2026, time.January, 6, 9, 0, 0, 0, time.UTC),
+			want:       []string{"Jan 6 09:00", "Jan 13 09:00", "Jan 27 11:00", "Feb 3 09:00", "Feb 10 09:00"},
+			wantSeries: 1,
+		},
+		{
+			name:       "single can not change the rule",
+			id:         *series.ID,
+			mode:       UpdateModeSingle,
+			occurrence: occurrence(12),
+			newStart:   january(12, 10),
+			rrule:      "FREQ=DAILY",
+			wantErr:    ErrInvalidRecurrence,
+		},
+		{
+			name:       "cancelled occurrence can not be updated",
+			id:         *series.ID,
+			mode:       UpdateModeSingle,
+			occurrence: occurrence(19),
+			newStart:   january(19, 10),
+			wantErr:    ErrOccurrenceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDayOffRepository(series, override)
+			usecase := NewUserDayOffUseCase(repo, repo.transaction, nil)
+
+			id := tt.id
+			newEnd := tt.newStart.Add(time.Hour)
+			dayOff := &entity_accounts.UserDayOff{ID: &id, InitHour: &tt.newStart, EndHour: &newEnd, RRule: tt.rrule}
+			err := usecase.Update(dayOff, 1, tt.mode, tt.occurrence)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Update() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+
+			if got := calendarOf(t, repo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calendar = %v, want %v", got, tt.want)
+			}
+			stored, _ := repo.FindAllByOwner(1)
+			gotSeries := 0
+			for _, dayOff := range stored {
+				if dayOff.DayOffFatherID == nil {
+					gotSeries++
+				}
+			}
+			if gotSeries != tt.wantSeries {
+				t.Errorf("stored %d series, want %d", gotSeries, tt.wantSeries)
+			}
+		})
+	}
+}
+
+func TestDeleteDayOffSeries(t *testing.T) {
+	series, override := seriesFixture()
+	occurrence := func(day int) *time.Time {
+		start := january(day, 9)
+		return &start
+	}
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		mode       string
+		occurrence *time.Time
+		want       []string
+		wantErr    error
+	}{
+		{
+			name:       "single occurrence is cancelled",
+			id:         *series.ID,
+			mode:       DeleteModeSingle,
+			occurrence: occurrence(12),
+			want:       []string{"Jan 5 09:00", "Jan 26 11:00", "Feb 2 09:00", "Feb 9 09:00"},
+		},
+		{
+			name: "single override is deleted and its occurrence cancelled",
+			id:   *override.ID,
+			mode: DeleteModeSingle,
+			want: []string{"Jan 5 09:00", "Jan 12 09:00", "Feb 2 09:00", "Feb 9 09:00"},
+		},
+		{
+			name:       "future ends the series with its later overrides",
+			id:         *series.ID,
+			mode:       DeleteModeFuture,
+			occurrence: occurrence(26),
+			want:       []string{"Jan 5 09:00", "Jan 12 09:00"},
+		},
+		{
+			name: "future from the first occurrence deletes the series",
+			id:   *series.ID,
+			mode: DeleteModeFuture,
+			want: []string{},
+		},
+		{
+			name: "all deletes the series from an override",
+			id:   *override.ID,
+			mode: DeleteModeAll,
+			want: []string{},
+		},
+		{
+			name:       "cancelled occurrence is not found",
+			id:         *series.ID,
+			mode:       DeleteModeSingle,
+			occurrence: occurrence(19),
+			wantErr:    ErrOccurrenceNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDayOffRepository(series, override)
+			usecase := NewUserDayOffUseCase(repo, repo.transaction, nil)
+
+			err := usecase.Delete(tt.id, 1, tt.mode, tt.occurrence)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if got := calendarOf(t, repo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calendar = %v, want %v", got, tt.want)
+			}
+			if len(tt.want) == 0 && len(repo.dayOffs) != 0 {
+				t.Errorf("%d day offs left, want none", len(repo.dayOffs))
+			}
+		})
+	}
+}
+
+func TestRestoreDayOffOccurrence(t *testing.T) {
+	series, override := seriesFixture()
+	id, start, end := uuid.New(), january(7, 9), january(7, 10)
+	single := &entity_accounts.UserDayOff{ID: &id, InitHour: &start, EndHour: &end, OwnerID: 1, TimeZone: "UTC"}
+
+	tests := []struct {
+		name       string
+		id         uuid.UUID
+		occurrence time.Time
+		want       []string
+		wantErr    error
+	}{
+		{
+			name:       "cancelled occurrence comes back",
+			id:         *series.ID,
+			occurrence: january(19, 9),
+			want:       []string{"Jan 5 09:00", "Jan 7 09:00", "Jan 12 09:00", "Jan 19 09:00", "Jan 26 11:00", "Feb 2 09:00", "Feb 9 09:00"},
+		},
+		{
+			name:       "override is deleted from the override id",
+			id:         *override.ID,
+			occurrence: january(26, 9),
+			want:       []string{"Jan 5 09:00", "Jan 7 09:00", "Jan 12 09:00", "Jan 26 09:00", "Feb 2 09:00", "Feb 9 09:00"},
+		},
+		{
+			name:       "occurrence following the rule is not found",
+			id:         *series.ID,
+			occurrence: january(12, 9),
+			wantErr:    ErrOccurrenceNotFound,
+		},
+		{
+			name:       "day off that does not repeat",
+			id:         *single.ID,
+			occurrence: january(7, 9),
+			wantErr:    ErrNotRecurring,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDayOffRepository(series, override, single)
+			usecase := NewUserDayOffUseCase(repo, repo.transaction, nil)
+
+			err := usecase.RestoreOccurrence(tt.id, 1, tt.occurrence)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RestoreOccurrence() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RestoreOccurrence() error = %v", err)
+			}
+			if got := calendarOf(t, repo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("calendar = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDayOffExceptions(t *testing.T) {
+	series, override := seriesFixture()
+	repo := newFakeDayOffRepository(series, override)
+	usecase := NewUserDayOffUseCase(repo, repo.transaction, nil)
+
+	// Read from an override, the exceptions are the ones of its series
+	exceptions, err := usecase.GetExceptions(*override.ID, 1)
+	if err != nil {
+		t.Fatalf("GetExceptions() error = %v", err)
+	}
+	if *exceptions.SeriesID != *series.ID {
+		t.Errorf("series id = %v, want %v", exceptions.SeriesID, series.ID)
+	}
+	if !reflect.DeepEqual(exceptions.ExDates, []time.Time{january(19, 9)}) {
+		t.Errorf("exdates = %v, want [%v]", exceptions.ExDates, january(19, 9))
+	}
+	if len(exceptions.Overrides) != 1 || *exceptions.Overrides[0].ID != *override.ID {
+		t.Errorf("overrides = %v, want the override of January 26", exceptions.Overrides)
+	}
+}
+
+func TestDayOffSeriesRollsBack(t *testing.T) {
+	series, override := seriesFixture()
+	base := []string{"Jan 5 09:00", "Jan 12 09:00", "Jan 26 11:00", "Feb 2 09:00", "Feb 9 09:00"}
+	occurrence := january(12, 9)
+	newStart, newEnd := january(12, 10), january(12, 11)
+
+	// Splitting creates the new series, ends the old one and moves the
+	// override; deleting ends the series and deletes the override
+	tests := []struct {
+		name      string
+		failWrite int
+		run       func(usecase IUseCaseUserDayOff) error
+	}{
+		{
+			name:      "update future fails creating the new series",
+			failWrite: 1,
+			run: func(usecase IUseCaseUserDayOff) error {
+				return usecase.Update(&entity_accounts.UserDayOff{ID: series.ID, InitHour: &newStart, EndHour: &newEnd}, 1, UpdateModeFuture, &occurrence)
+			},
+		},
+		{
+			name:      "update future fails ending the old series",
+			failWrite: 2,
+			run: func(usecase IUseCaseUserDayOff) error {
+				return usecase.Update(&entity_accounts.UserDayOff{ID: series.ID, InitHour: &newStart, EndHour: &newEnd}, 1, UpdateModeFuture, &occurrence)
+			},
+		},
+		{
+			name:      "update future fails moving the override",
+			failWrite: 3,
+			run: func(usecase IUseCaseUserDayOff) error {
+				return usecase.Update(&entity_accounts.UserDayOff{ID: series.ID, InitHour: &newStart, EndHour: &newEnd}, 1, UpdateModeFuture, &occurrence)
+			},
+		},
+		{
+			name:      "update all fails moving the override",
+			failWrite: 2,
+			run: func(usecase IUseCaseUserDayOff) error {
+				return usecase.Update(&entity_accounts.UserDayOff{ID: series.ID, InitHour: &newStart, EndHour: &newEnd}, 1, UpdateModeAll, &occurrence)
+			},
+		},
+		{
+			name:      "delete future fails deleting the override",
+			failWrite: 2,
+			run: func(usecase IUseCaseUserDayOff) error {
+				return usecase.Delete(*series.ID, 1, DeleteModeFuture, &occurrence)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeDayOffRepository(series, override)
+			want := newFakeDayOffRepository(series, override).dayOffs
+			repo.failWrite = tt.failWrite
+			usecase := NewUserDayOffUseCase(repo, repo.transaction, nil)
+
+			if err := tt.run(usecase); err == nil {
+				t.Fatalf("error = nil, want the failed write")
+			}
+			if repo.writes != tt.failWrite {
+				t.Errorf("%d writes, want %d, stopping at the failed one", repo.writes, tt.failWrite)
+			}
+			if !reflect.DeepEqual(repo.dayOffs, want) {
+				t.Errorf("day offs were changed by the failed operation")
+			}
+			if got := calendarOf(t, repo); !reflect.DeepEqual(got, base) {
+				t.Errorf("calendar = %v, want %v", got, base)
+			}
+		})
+	}
+}
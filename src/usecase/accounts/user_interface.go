@@ -6,6 +6,7 @@ type IRepositoryUser interface {
 	Create(user *entity_accounts.User) error
 	FindById(id int) (*entity_accounts.User, error)
 	FindByEmail(email string) (*entity_accounts.User, error)
+	FindByCalendarToken(token string) (*entity_accounts.User, error)
 	Update(user *entity_accounts.User) error
 }
 
@@ -15,4 +16,7 @@ type IUseCaseUser interface {
 	FindById(id int) (*entity_accounts.User, error)
 	FindByEmail(email string) (*entity_accounts.User, error)
 	Update(user *entity_accounts.User) error
+	GetCalendarToken(id int) (string, error)
+	ResetCalendarToken(id int) (string, error)
+	SetTimeZone(id int, timeZone string) error
 }
@@ -9,6 +9,8 @@ import (
 type IRepositoryUserPix interface {
 	Create(userPix *entity_accounts.UserPix) error
 	FindByIdAndOwner(id uuid.UUID, ownerID int) (*entity_accounts.UserPix, error)
+	FindByKeyAndOwner(pixKey string, ownerID int) (*entity_accounts.UserPix, error)
+	FindPrimaryByOwner(ownerID int) (*entity_accounts.UserPix, error)
 	GetAllByOwner(ownerID int) ([]*entity_accounts.UserPix, error)
 	Delete(id uuid.UUID) error
 	Update(userPix *entity_accounts.UserPix) error
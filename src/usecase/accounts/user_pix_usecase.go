@@ -2,11 +2,29 @@ package usecase_accounts
 
 import (
 	entity_accounts "app/entity/accounts"
+	"app/utils/pix"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/google/uuid"
 )
 
+var ErrPixNotFound = errors.New("pix key not found or access denied")
+
+// FieldErrors maps an input field to the reason it was rejected.
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	messages := []string{}
+	for field, message := range e {
+		messages = append(messages, field+": "+message)
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
+
 type userPixUseCase struct {
 	repo IRepositoryUserPix
 }
@@ -24,6 +42,17 @@ func (u *userPixUseCase) Create(userPix *entity_accounts.UserPix, ownerID int) e
 	// Or we can just set `Owner: &entity_accounts.User{ID: ownerID}`.
 
 	userPix.OwnerID = ownerID
+	if err := validatePixKey(userPix); err != nil {
+		return err
+	}
+	if err := u.checkDuplicate(userPix); err != nil {
+		return err
+	}
+
+	// The first key is always primary so payees always have one
+	if _, err := u.repo.FindPrimaryByOwner(ownerID); err != nil {
+		userPix.IsPrimary = true
+	}
 
 	if err := u.repo.Create(userPix); err != nil {
 		return fmt.Errorf("could not create user pix key")
@@ -31,10 +60,44 @@ func (u *userPixUseCase) Create(userPix *entity_accounts.UserPix, ownerID int) e
 	return nil
 }
 
+// validatePixKey infers the key type when it is not declared, validates the
+// key against it and stores the key normalized.
+func validatePixKey(userPix *entity_accounts.UserPix) error {
+	keyType := strings.ToLower(strings.TrimSpace(userPix.KeyType))
+	if keyType == "" {
+		keyType = pix.DetectKeyType(userPix.PixKey)
+		if keyType == "" {
+			return FieldErrors{"pix_key": "could not infer the key type, please send key_type"}
+		}
+	} else if !pix.IsValidKeyType(keyType) {
+		return FieldErrors{"key_type": "must be one of cpf, cnpj, email, phone or evp"}
+	}
+
+	key, err := pix.NormalizeKey(keyType, userPix.PixKey)
+	if err != nil {
+		return FieldErrors{"pix_key": err.Error()}
+	}
+	userPix.PixKey = key
+	userPix.KeyType = keyType
+	return nil
+}
+
+// checkDuplicate rejects a key the owner already has under another ID.
+func (u *userPixUseCase) checkDuplicate(userPix *entity_accounts.UserPix) error {
+	existing, err := u.repo.FindByKeyAndOwner(userPix.PixKey, userPix.OwnerID)
+	if err != nil {
+		return nil
+	}
+	if userPix.ID == nil || *existing.ID != *userPix.ID {
+		return FieldErrors{"pix_key": "key is already registered"}
+	}
+	return nil
+}
+
 func (u *userPixUseCase) GetById(id uuid.UUID, ownerID int) (*entity_accounts.UserPix, error) {
 	userPix, err := u.repo.FindByIdAndOwner(id, ownerID)
 	if err != nil {
-		return nil, fmt.Errorf("pix key not found or access denied")
+		return nil, ErrPixNotFound
 	}
 	return userPix, nil
 }
@@ -47,7 +110,7 @@ func (u *userPixUseCase) Delete(id uuid.UUID, ownerID int) error {
 	// Verify ownership first
 	_, err := u.repo.FindByIdAndOwner(id, ownerID)
 	if err != nil {
-		return fmt.Errorf("pix key not found or access denied")
+		return ErrPixNotFound
 	}
 
 	if err := u.repo.Delete(id); err != nil {
@@ -64,15 +127,26 @@ func (u *userPixUseCase) Update(userPix *entity_accounts.UserPix, ownerID int) e
 
 	existing, err := u.repo.FindByIdAndOwner(*userPix.ID, ownerID)
 	if err != nil {
-		return fmt.Errorf("pix key not found or access denied")
+		return ErrPixNotFound
 	}
 
 	// Update allowed fields
 	existing.PixKey = userPix.PixKey
-	// ... update other fields if any ...
+	existing.KeyType = userPix.KeyType
+	if err := validatePixKey(existing); err != nil {
+		return err
+	}
+	if err := u.checkDuplicate(existing); err != nil {
+		return err
+	}
+	// The primary key only moves by promoting another key
+	if userPix.IsPrimary {
+		existing.IsPrimary = true
+	}
 
 	if err := u.repo.Update(existing); err != nil {
 		return fmt.Errorf("could not update pix key")
 	}
+	*userPix = *existing
 	return nil
 }
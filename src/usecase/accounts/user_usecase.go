@@ -2,9 +2,31 @@ package usecase_accounts
 
 import (
 	entity_accounts "app/entity/accounts"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"time"
 )
 
+var (
+	ErrInvalidCalendarToken = errors.New("invalid calendar token")
+	ErrInvalidTimeZone      = errors.New("invalid time zone")
+)
+
+// loadTimeZone returns the location of an IANA time zone name, like
+// America/Sao_Paulo. An empty name is UTC.
+func loadTimeZone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTimeZone, name)
+	}
+	return loc, nil
+}
+
 type userUseCase struct {
 	repo IRepositoryUser
 }
@@ -14,6 +36,9 @@ func NewUserUseCase(repo IRepositoryUser) IUseCaseUser {
 }
 
 func (u *userUseCase) Register(user *entity_accounts.User) error {
+	if _, err := loadTimeZone(user.TimeZone); err != nil {
+		return err
+	}
 	err := u.repo.Create(user)
 	if err != nil {
 		return fmt.Errorf("could not create user")
@@ -55,3 +80,62 @@ func (u *userUseCase) Update(user *entity_accounts.User) error {
 	}
 	return nil
 }
+
+// SetTimeZone changes the time zone day offs are listed in. The day offs
+// already created keep the time zone they repeat in.
+func (u *userUseCase) SetTimeZone(id int, timeZone string) error {
+	loc, err := loadTimeZone(timeZone)
+	if err != nil {
+		return err
+	}
+	user, err := u.repo.FindById(id)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+	user.TimeZone = loc.String()
+	if err := u.repo.Update(user); err != nil {
+		return fmt.Errorf("could not update user")
+	}
+	return nil
+}
+
+// generateCalendarToken returns a random hex secret with 256 bits of entropy.
+func generateCalendarToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GetCalendarToken returns the secret of the user's calendar feeds, creating
+// it on first use.
+func (u *userUseCase) GetCalendarToken(id int) (string, error) {
+	user, err := u.repo.FindById(id)
+	if err != nil {
+		return "", fmt.Errorf("user not found")
+	}
+	if user.CalendarToken != nil {
+		return *user.CalendarToken, nil
+	}
+	return u.ResetCalendarToken(id)
+}
+
+// ResetCalendarToken replaces the secret of the user's calendar feeds, so
+// the previous feed URLs stop working.
+func (u *userUseCase) ResetCalendarToken(id int) (string, error) {
+	user, err := u.repo.FindById(id)
+	if err != nil {
+		return "", fmt.Errorf("user not found")
+	}
+
+	token, err := generateCalendarToken()
+	if err != nil {
+		return "", fmt.Errorf("could not generate calendar token")
+	}
+	user.CalendarToken = &token
+	if err := u.repo.Update(user); err != nil {
+		return "", fmt.Errorf("could not update user")
+	}
+	return token, nil
+}
@@ -0,0 +1,26 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SuggestionOptions configures how candidate session slots are generated and scored.
+type SuggestionOptions struct {
+	From              time.Time
+	To                time.Time
+	Duration          time.Duration
+	Limit             int
+	MinMembers        int
+	PreferredFromHour int // Inclusive, local hour in Location
+	PreferredToHour   int // Exclusive, local hour in Location; may wrap past midnight
+	PreferredWeekdays []time.Weekday
+	Location          *time.Location
+}
+
+type IUseCaseCrewAvailability interface {
+	GetAvailability(crewID uuid.UUID, userID int, from, to time.Time, minMembers int) (*entity_crew.CrewAvailability, error)
+	SuggestSlots(crewID uuid.UUID, userID int, options SuggestionOptions) ([]*entity_crew.SessionSuggestion, error)
+}
@@ -0,0 +1,195 @@
+package usecase_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	usecase_accounts "app/usecase/accounts"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxAvailabilityRange bounds the window a single availability query may span.
+const MaxAvailabilityRange = 366 * 24 * time.Hour
+
+type crewAvailabilityUseCase struct {
+	repoCrew   IRepositoryCrew
+	repoMember IRepositoryCrewMember
+	repoDayOff usecase_accounts.IRepositoryUserDayOff
+}
+
+func NewCrewAvailabilityUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoDayOff usecase_accounts.IRepositoryUserDayOff) IUseCaseCrewAvailability {
+	return &crewAvailabilityUseCase{repoCrew: repoCrew, repoMember: repoMember, repoDayOff: repoDayOff}
+}
+
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// mergeIntervals clips intervals to [from, to) and merges overlapping or
+// touching ones. The result is sorted by start.
+func mergeIntervals(intervals []interval, from, to time.Time) []interval {
+	clipped := []interval{}
+	for _, i := range intervals {
+		if i.start.Before(from) {
+			i.start = from
+		}
+		if i.end.After(to) {
+			i.end = to
+		}
+		if i.end.After(i.start) {
+			clipped = append(clipped, i)
+		}
+	}
+	sort.Slice(clipped, func(a, b int) bool { return clipped[a].start.Before(clipped[b].start) })
+
+	merged := []interval{}
+	for _, i := range clipped {
+		last := len(merged) - 1
+		if last >= 0 && !i.start.After(merged[last].end) {
+			if i.end.After(merged[last].end) {
+				merged[last].end = i.end
+			}
+			continue
+		}
+		merged = append(merged, i)
+	}
+	return merged
+}
+
+// intersectAvailability sweeps over the free intervals of every member and
+// returns the windows where at least minMembers are free at the same time.
+// Consecutive windows with the same set of members are merged into one slot.
+func intersectAvailability(free map[int][]interval, users map[int]*entity_accounts.User, from, to time.Time, minMembers int) []*entity_crew.AvailabilitySlot {
+	type event struct {
+		at     time.Time
+		userID int
+		delta  int
+	}
+
+	events := []event{}
+	for userID, intervals := range free {
+		for _, i := range mergeIntervals(intervals, from, to) {
+			events = append(events, event{at: i.start, userID: userID, delta: 1}, event{at: i.end, userID: userID, delta: -1})
+		}
+	}
+	sort.Slice(events, func(a, b int) bool { return events[a].at.Before(events[b].at) })
+
+	slots := []*entity_crew.AvailabilitySlot{}
+	active := map[int]bool{}
+	var lastIDs []int
+
+	for idx := 0; idx < len(events); {
+		at := events[idx].at
+		for idx < len(events) && events[idx].at.Equal(at) {
+			if events[idx].delta > 0 {
+				active[events[idx].userID] = true
+			} else {
+				delete(active, events[idx].userID)
+			}
+			idx++
+		}
+		if idx == len(events) || len(active) < minMembers {
+			lastIDs = nil
+			continue
+		}
+
+		ids := make([]int, 0, len(active))
+		for userID := range active {
+			ids = append(ids, userID)
+		}
+		sort.Ints(ids)
+		next := events[idx].at
+
+		if lastIDs != nil && equalIDs(lastIDs, ids) {
+			slots[len(slots)-1].EndAt = next
+			continue
+		}
+
+		slot := &entity_crew.AvailabilitySlot{StartAt: at, EndAt: next, AvailableCount: len(ids)}
+		for _, userID := range ids {
+			slot.AvailableMembers = append(slot.AvailableMembers, users[userID])
+		}
+		slots = append(slots, slot)
+		lastIDs = ids
+	}
+	return slots
+}
+
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// loadFreeIntervals returns, for each member, the day offs overlapping [from, to),
+// including the occurrences of their recurring day offs.
+func loadFreeIntervals(repoDayOff usecase_accounts.IRepositoryUserDayOff, members []*entity_crew.CrewMember, from, to time.Time) (map[int][]interval, error) {
+	free := map[int][]interval{}
+	for _, member := range members {
+		dayOffs, err := usecase_accounts.DayOffsBetween(repoDayOff, member.UserID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, dayOff := range dayOffs {
+			if dayOff.InitHour == nil || dayOff.EndHour == nil {
+				continue
+			}
+			free[member.UserID] = append(free[member.UserID], interval{start: *dayOff.InitHour, end: *dayOff.EndHour})
+		}
+	}
+	return free, nil
+}
+
+func (u *crewAvailabilityUseCase) GetAvailability(crewID uuid.UUID, userID int, from, to time.Time, minMembers int) (*entity_crew.CrewAvailability, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("'to' must be after 'from'")
+	}
+	if to.Sub(from) > MaxAvailabilityRange {
+		return nil, fmt.Errorf("date range must not exceed 366 days")
+	}
+
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return nil, fmt.Errorf("could not load crew members")
+	}
+
+	// Without min_members everybody has to be free.
+	if minMembers == 0 {
+		minMembers = len(members)
+	}
+	if minMembers < 1 || minMembers > len(members) {
+		return nil, fmt.Errorf("min_members must be between 1 and %d", len(members))
+	}
+
+	free, err := loadFreeIntervals(u.repoDayOff, members, from, to)
+	if err != nil {
+		return nil, err
+	}
+	users := map[int]*entity_accounts.User{}
+	for _, member := range members {
+		users[member.UserID] = member.User
+	}
+
+	return &entity_crew.CrewAvailability{
+		CrewID:       crewID,
+		From:         from,
+		To:           to,
+		MinMembers:   minMembers,
+		TotalMembers: len(members),
+		Slots:        intersectAvailability(free, users, from, to, minMembers),
+	}, nil
+}
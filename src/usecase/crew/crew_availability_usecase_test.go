@@ -0,0 +1,201 @@
+package usecase_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeDayOffRepository keeps the day offs of the members in memory.
+type fakeDayOffRepository struct {
+	dayOffs []*entity_accounts.UserDayOff
+}
+
+// add stores a day off of ownerID from start to end, repeating by rule when
+// it is set.
+func (r *fakeDayOffRepository) add(ownerID int, start, end time.Time, rule string) {
+	id := uuid.New()
+	r.dayOffs = append(r.dayOffs, &entity_accounts.UserDayOff{ID: &id, OwnerID: ownerID, InitHour: &start, EndHour: &end, RRule: rule, TimeZone: "UTC"})
+}
+
+func (r *fakeDayOffRepository) find(match func(dayOff *entity_accounts.UserDayOff) bool) []*entity_accounts.UserDayOff {
+	dayOffs := []*entity_accounts.UserDayOff{}
+	for _, dayOff := range r.dayOffs {
+		if match(dayOff) {
+			dayOffs = append(dayOffs, dayOff)
+		}
+	}
+	return dayOffs
+}
+
+func (r *fakeDayOffRepository) Create(dayOff *entity_accounts.UserDayOff) error {
+	r.dayOffs = append(r.dayOffs, dayOff)
+	return nil
+}
+
+func (r *fakeDayOffRepository) FindByIdAndOwner(id uuid.UUID, ownerID int) (*entity_accounts.UserDayOff, error) {
+	for _, dayOff := range r.dayOffs {
+		if *dayOff.ID == id && dayOff.OwnerID == ownerID {
+			return dayOff, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeDayOffRepository) FindAllByOwner(ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.find(func(dayOff *entity_accounts.UserDayOff) bool { return dayOff.OwnerID == ownerID }), nil
+}
+
+func (r *fakeDayOffRepository) FindAllByOwnerWithFilter(ownerID int, startDate, endDate *time.Time) ([]*entity_accounts.UserDayOff, error) {
+	return r.FindAllByOwner(ownerID)
+}
+
+func (r *fakeDayOffRepository) FindAllByFather(fatherID uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.FindAllByFathers([]uuid.UUID{fatherID}, ownerID)
+}
+
+func (r *fakeDayOffRepository) FindAllByFathers(fatherIDs []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return r.find(func(dayOff *entity_accounts.UserDayOff) bool {
+		for _, id := range fatherIDs {
+			if dayOff.DayOffFatherID != nil && *dayOff.DayOffFatherID == id {
+				return dayOff.OwnerID == ownerID
+			}
+		}
+		return false
+	}), nil
+}
+
+func (r *fakeDayOffRepository) FindAllByIdsAndOwner(ids []uuid.UUID, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return nil, nil
+}
+
+func (r *fakeDayOffRepository) FindAllByICalUIDs(uids []string, ownerID int) ([]*entity_accounts.UserDayOff, error) {
+	return nil, nil
+}
+
+func (r *fakeDayOffRepository) DeleteById(id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeDayOffRepository) DeleteBatch(ids []uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeDayOffRepository) Update(dayOff *entity_accounts.UserDayOff) error {
+	return nil
+}
+
+// at returns the time of January 10 2026, a Saturday, at hour UTC.
+func at(hour int) time.Time {
+	return time.Date(2026, time.January, 10, hour, 0, 0, 0, time.UTC)
+}
+
+// newAvailabilityFixture gives the members of the crew fixture their day
+// offs on January 10: the owner is free 09-17, in two touching day offs,
+// the admin 12-20 and the member 14-16 every day. The other member is
+// never free.
+func newAvailabilityFixture() (*entity_crew.Crew, IUseCaseCrewAvailability) {
+	crew, repoCrew, repoMember, _ := newCrewFixture()
+	repoDayOff := &fakeDayOffRepository{}
+	repoDayOff.add(userOwner, at(9), at(12), "")
+	repoDayOff.add(userOwner, at(12), at(17), "")
+	repoDayOff.add(userAdmin, at(12), at(20), "")
+	repoDayOff.add(userMember, at(14).AddDate(0, 0, -1), at(16).AddDate(0, 0, -1), "FREQ=DAILY;COUNT=3")
+	return crew, NewCrewAvailabilityUseCase(repoCrew, repoMember, repoDayOff)
+}
+
+// formatSlot writes a slot as "12-14 Ana Bruno".
+func formatSlot(start, end time.Time, members []*entity_accounts.User) string {
+	return fmt.Sprintf("%02d-%02d%s", start.Hour(), end.Hour(), formatMembers(members))
+}
+
+func formatMembers(members []*entity_accounts.User) string {
+	value := ""
+	for _, member := range members {
+		value += " " + member.Name
+	}
+	return value
+}
+
+func TestGetAvailability(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     int
+		from, to   time.Time
+		minMembers int
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name:       "three of four members",
+			userID:     userMember,
+			from:       at(0),
+			to:         at(24),
+			minMembers: 3,
+			want:       []string{"14-16 Ana Bruno Carla"},
+		},
+		{
+			name:       "two of four members split where the members change",
+			userID:     userMember,
+			from:       at(0),
+			to:         at(24),
+			minMembers: 2,
+			want:       []string{"12-14 Ana Bruno", "14-16 Ana Bruno Carla", "16-17 Ana Bruno"},
+		},
+		{
+			name:       "one member merges touching day offs",
+			userID:     userMember,
+			from:       at(0),
+			to:         at(13),
+			minMembers: 1,
+			want:       []string{"09-12 Ana", "12-13 Ana Bruno"},
+		},
+		{
+			name:   "everyone by default",
+			userID: userMember,
+			from:   at(0),
+			to:     at(24),
+			want:   []string{},
+		},
+		{name: "more members than the crew has", userID: userMember, from: at(0), to: at(24), minMembers: 5, wantErr: true},
+		{name: "negative minimum", userID: userMember, from: at(0), to: at(24), minMembers: -1, wantErr: true},
+		{name: "empty range", userID: userMember, from: at(12), to: at(12), wantErr: true},
+		{name: "outsider", userID: userOutsider, from: at(0), to: at(24), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, usecase := newAvailabilityFixture()
+
+			availability, err := usecase.GetAvailability(*crew.ID, tt.userID, tt.from, tt.to, tt.minMembers)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("GetAvailability() = %v, want an error", availability)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetAvailability() error = %v", err)
+			}
+			got := []string{}
+			for _, slot := range availability.Slots {
+				if slot.AvailableCount != len(slot.AvailableMembers) {
+					t.Errorf("slot %v counts %d members, want %d", slot.StartAt, slot.AvailableCount, len(slot.AvailableMembers))
+				}
+				got = append(got, formatSlot(slot.StartAt, slot.EndAt, slot.AvailableMembers))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("slots = %v, want %v", got, tt.want)
+			}
+			if availability.TotalMembers != 4 {
+				t.Errorf("total members = %d, want 4", availability.TotalMembers)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package usecase_crew
+
+import (
+	"app/utils/ical"
+
+	"github.com/google/uuid"
+)
+
+type IUseCaseCrewCalendar interface {
+	UserCalendar(calendarToken string) (*ical.Calendar, error)
+	CrewCalendar(crewID uuid.UUID, calendarToken string) (*ical.Calendar, error)
+}
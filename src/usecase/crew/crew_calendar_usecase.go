@@ -0,0 +1,139 @@
+package usecase_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	usecase_accounts "app/usecase/accounts"
+	"app/utils/ical"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CalendarProdID = "-//Movie Friends//Calendar//EN"
+	// Past sessions older than this are left out of the feeds
+	calendarSessionHistory = 90 * 24 * time.Hour
+)
+
+type crewCalendarUseCase struct {
+	repoCrew    IRepositoryCrew
+	repoMember  IRepositoryCrewMember
+	repoSession IRepositoryMovieSession
+	repoRSVP    IRepositorySessionRSVP
+	repoUser    usecase_accounts.IRepositoryUser
+	repoDayOff  usecase_accounts.IRepositoryUserDayOff
+	domain      string
+}
+
+func NewCrewCalendarUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoSession IRepositoryMovieSession, repoRSVP IRepositorySessionRSVP, repoUser usecase_accounts.IRepositoryUser, repoDayOff usecase_accounts.IRepositoryUserDayOff, domain string) IUseCaseCrewCalendar {
+	return &crewCalendarUseCase{
+		repoCrew:    repoCrew,
+		repoMember:  repoMember,
+		repoSession: repoSession,
+		repoRSVP:    repoRSVP,
+		repoUser:    repoUser,
+		repoDayOff:  repoDayOff,
+		domain:      domain,
+	}
+}
+
+func (u *crewCalendarUseCase) findUser(calendarToken string) (*entity_accounts.User, error) {
+	if calendarToken == "" {
+		return nil, usecase_accounts.ErrInvalidCalendarToken
+	}
+	user, err := u.repoUser.FindByCalendarToken(calendarToken)
+	if err != nil {
+		return nil, usecase_accounts.ErrInvalidCalendarToken
+	}
+	return user, nil
+}
+
+func (u *crewCalendarUseCase) sessionEvent(crew *entity_crew.Crew, session *entity_crew.MovieSession) *ical.Event {
+	event := &ical.Event{
+		UID:         session.ID.String() + "@" + u.domain,
+		Stamp:       session.UpdatedAt,
+		Start:       *session.StartAt,
+		End:         *session.EndAt,
+		Summary:     session.Title + " (" + crew.Name + ")",
+		Description: session.Description,
+		Status:      ical.StatusConfirmed,
+	}
+	if session.LocationType == entity_crew.LocationTypeStreaming {
+		event.Location = session.StreamingURL
+		event.URL = session.StreamingURL
+	} else {
+		event.Location = session.Location
+	}
+	if session.Movie != nil && !strings.EqualFold(session.Movie.Title, session.Title) {
+		event.Description = strings.TrimSpace("Movie: " + session.Movie.Title + "\n\n" + session.Description)
+	}
+	return event
+}
+
+// UserCalendar lists the user's day offs and the sessions of their crews,
+// leaving out sessions they are not going to. Sessions they did not confirm
+// are tentative.
+func (u *crewCalendarUseCase) UserCalendar(calendarToken string) (*ical.Calendar, error) {
+	user, err := u.findUser(calendarToken)
+	if err != nil {
+		return nil, err
+	}
+
+	dayOffs, err := u.repoDayOff.FindAllByOwner(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load day offs")
+	}
+	crews, err := u.repoCrew.FindAllByMember(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load crews")
+	}
+
+	events := usecase_accounts.DayOffEvents(dayOffs, u.domain)
+	from := time.Now().Add(-calendarSessionHistory)
+	for _, crew := range crews {
+		sessions, err := u.repoSession.FindAllByCrewWithFilter(*crew.ID, &from, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not load sessions")
+		}
+		for _, session := range sessions {
+			event := u.sessionEvent(crew, session)
+			rsvp, err := u.repoRSVP.FindBySessionAndUser(*session.ID, user.ID)
+			switch {
+			case err != nil || rsvp.Status == entity_crew.RSVPStatusMaybe || rsvp.Status == entity_crew.RSVPStatusWaitlisted:
+				event.Status = ical.StatusTentative
+			case rsvp.Status == entity_crew.RSVPStatusNotGoing:
+				continue
+			}
+			events = append(events, event)
+		}
+	}
+
+	return &ical.Calendar{ProdID: CalendarProdID, Name: user.Name, Events: events}, nil
+}
+
+// CrewCalendar lists the sessions of a crew the token's user belongs to.
+func (u *crewCalendarUseCase) CrewCalendar(crewID uuid.UUID, calendarToken string) (*ical.Calendar, error) {
+	user, err := u.findUser(calendarToken)
+	if err != nil {
+		return nil, err
+	}
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now().Add(-calendarSessionHistory)
+	sessions, err := u.repoSession.FindAllByCrewWithFilter(crewID, &from, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not load sessions")
+	}
+	events := []*ical.Event{}
+	for _, session := range sessions {
+		events = append(events, u.sessionEvent(crew, session))
+	}
+
+	return &ical.Calendar{ProdID: CalendarProdID, Name: crew.Name, Events: events}, nil
+}
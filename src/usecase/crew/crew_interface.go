@@ -0,0 +1,24 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+type IRepositoryCrew interface {
+	Create(crew *entity_crew.Crew) error
+	FindById(id uuid.UUID) (*entity_crew.Crew, error)
+	FindAllByOwner(ownerID int) ([]*entity_crew.Crew, error)
+	FindAllByMember(userID int) ([]*entity_crew.Crew, error)
+	Update(crew *entity_crew.Crew) error
+	Delete(id uuid.UUID) error
+}
+
+type IUseCaseCrew interface {
+	Create(crew *entity_crew.Crew, ownerID int) error
+	GetById(id uuid.UUID, userID int) (*entity_crew.Crew, error)
+	GetAll(userID int) ([]*entity_crew.Crew, error)
+	Update(crew *entity_crew.Crew, userID int) error
+	Delete(id uuid.UUID, userID int) error
+}
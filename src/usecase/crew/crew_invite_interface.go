@@ -0,0 +1,30 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IRepositoryCrewInvite interface {
+	Create(invite *entity_crew.CrewInvite) error
+	FindById(id uuid.UUID) (*entity_crew.CrewInvite, error)
+	FindByCode(code string) (*entity_crew.CrewInvite, error)
+	FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewInvite, error)
+	FindAllPendingByInvitee(inviteeID int) ([]*entity_crew.CrewInvite, error)
+	FindPendingByCrewAndInvitee(crewID uuid.UUID, inviteeID int) (*entity_crew.CrewInvite, error)
+	Update(invite *entity_crew.CrewInvite) error
+	Redeem(inviteID uuid.UUID, member *entity_crew.CrewMember) (bool, error)
+}
+
+type IUseCaseCrewInvite interface {
+	CreateCodeInvite(crewID uuid.UUID, userID int, expiresIn time.Duration, maxUses int) (*entity_crew.CrewInvite, error)
+	CreateEmailInvite(crewID uuid.UUID, userID int, email string, expiresIn time.Duration) (*entity_crew.CrewInvite, error)
+	GetAllByCrew(crewID uuid.UUID, userID int) ([]*entity_crew.CrewInvite, error)
+	GetPending(userID int) ([]*entity_crew.CrewInvite, error)
+	Revoke(crewID uuid.UUID, inviteID uuid.UUID, userID int) error
+	Accept(inviteID uuid.UUID, userID int) (*entity_crew.CrewMember, error)
+	AcceptCode(code string, userID int) (*entity_crew.CrewMember, error)
+	Decline(inviteID uuid.UUID, userID int) error
+}
@@ -0,0 +1,244 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	usecase_accounts "app/usecase/accounts"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const DefaultInviteExpiration = 7 * 24 * time.Hour
+
+var ErrInviteNotFound = errors.New("invite not found")
+
+type crewInviteUseCase struct {
+	repoCrew   IRepositoryCrew
+	repoMember IRepositoryCrewMember
+	repoInvite IRepositoryCrewInvite
+	repoUser   usecase_accounts.IRepositoryUser
+}
+
+func NewCrewInviteUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoInvite IRepositoryCrewInvite, repoUser usecase_accounts.IRepositoryUser) IUseCaseCrewInvite {
+	return &crewInviteUseCase{repoCrew: repoCrew, repoMember: repoMember, repoInvite: repoInvite, repoUser: repoUser}
+}
+
+// generateInviteCode returns a random, URL-safe code with 50 bits of entropy.
+func generateInviteCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)[:10], nil
+}
+
+func (u *crewInviteUseCase) newInvite(crewID uuid.UUID, userID int, expiresIn time.Duration) (*entity_crew.CrewInvite, error) {
+	crew, member, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.CanManageMembers() {
+		return nil, ErrCrewForbidden
+	}
+
+	if expiresIn <= 0 {
+		expiresIn = DefaultInviteExpiration
+	}
+	expiresAt := time.Now().Add(expiresIn)
+
+	return &entity_crew.CrewInvite{
+		CrewID:     crew.ID,
+		IssuedByID: userID,
+		Status:     entity_crew.InviteStatusPending,
+		ExpiresAt:  &expiresAt,
+	}, nil
+}
+
+// refreshStatus persists the expired status of invites whose expiry date passed.
+func (u *crewInviteUseCase) refreshStatus(invite *entity_crew.CrewInvite) {
+	if invite.IsExpired(time.Now()) {
+		invite.Status = entity_crew.InviteStatusExpired
+		invite.UpdatedAt = time.Now()
+		_ = u.repoInvite.Update(invite)
+	}
+}
+
+func (u *crewInviteUseCase) CreateCodeInvite(crewID uuid.UUID, userID int, expiresIn time.Duration, maxUses int) (*entity_crew.CrewInvite, error) {
+	if maxUses < 0 {
+		return nil, fmt.Errorf("max_uses must not be negative")
+	}
+
+	invite, err := u.newInvite(crewID, userID, expiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate invite code")
+	}
+	invite.Code = &code
+	invite.MaxUses = maxUses
+
+	if err := u.repoInvite.Create(invite); err != nil {
+		return nil, fmt.Errorf("could not create invite")
+	}
+	return invite, nil
+}
+
+func (u *crewInviteUseCase) CreateEmailInvite(crewID uuid.UUID, userID int, email string, expiresIn time.Duration) (*entity_crew.CrewInvite, error) {
+	invite, err := u.newInvite(crewID, userID, expiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	invitee, err := u.repoUser.FindByEmail(strings.TrimSpace(email))
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, invitee.ID); err == nil {
+		return nil, fmt.Errorf("user is already a member of the crew")
+	}
+	if existing, err := u.repoInvite.FindPendingByCrewAndInvitee(crewID, invitee.ID); err == nil {
+		u.refreshStatus(existing)
+		if existing.Status == entity_crew.InviteStatusPending {
+			return nil, fmt.Errorf("user already has a pending invite to the crew")
+		}
+	}
+
+	invite.InviteeID = &invitee.ID
+
+	if err := u.repoInvite.Create(invite); err != nil {
+		return nil, fmt.Errorf("could not create invite")
+	}
+	invite.Invitee = invitee
+	return invite, nil
+}
+
+func (u *crewInviteUseCase) GetAllByCrew(crewID uuid.UUID, userID int) ([]*entity_crew.CrewInvite, error) {
+	_, member, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.CanManageMembers() {
+		return nil, ErrCrewForbidden
+	}
+
+	invites, err := u.repoInvite.FindAllByCrew(crewID)
+	if err != nil {
+		return nil, err
+	}
+	for _, invite := range invites {
+		u.refreshStatus(invite)
+	}
+	return invites, nil
+}
+
+func (u *crewInviteUseCase) GetPending(userID int) ([]*entity_crew.CrewInvite, error) {
+	invites, err := u.repoInvite.FindAllPendingByInvitee(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []*entity_crew.CrewInvite{}
+	for _, invite := range invites {
+		u.refreshStatus(invite)
+		if invite.Status == entity_crew.InviteStatusPending {
+			pending = append(pending, invite)
+		}
+	}
+	return pending, nil
+}
+
+func (u *crewInviteUseCase) Revoke(crewID uuid.UUID, inviteID uuid.UUID, userID int) error {
+	_, member, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return err
+	}
+	if !member.CanManageMembers() {
+		return ErrCrewForbidden
+	}
+
+	invite, err := u.repoInvite.FindById(inviteID)
+	if err != nil || *invite.CrewID != crewID {
+		return ErrInviteNotFound
+	}
+	u.refreshStatus(invite)
+	if invite.Status != entity_crew.InviteStatusPending {
+		return fmt.Errorf("only pending invites can be revoked")
+	}
+
+	invite.Status = entity_crew.InviteStatusRevoked
+	invite.UpdatedAt = time.Now()
+	if err := u.repoInvite.Update(invite); err != nil {
+		return fmt.Errorf("could not revoke invite")
+	}
+	return nil
+}
+
+// join adds userID to the invite's crew and records the use of the invite.
+func (u *crewInviteUseCase) join(invite *entity_crew.CrewInvite, userID int) (*entity_crew.CrewMember, error) {
+	u.refreshStatus(invite)
+	if invite.Status != entity_crew.InviteStatusPending {
+		return nil, fmt.Errorf("invite is %s", invite.Status)
+	}
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, *invite.CrewID, userID); err == nil {
+		return nil, fmt.Errorf("user is already a member of the crew")
+	}
+
+	member := entity_crew.CrewMember{
+		CrewID: invite.CrewID,
+		UserID: userID,
+		Role:   entity_crew.CREW_ROLE_MEMBER,
+	}
+	// Code invites stay usable until they run out of uses, expire or are revoked.
+	redeemed, err := u.repoInvite.Redeem(*invite.ID, &member)
+	if err != nil {
+		return nil, fmt.Errorf("could not join crew")
+	}
+	if !redeemed {
+		return nil, fmt.Errorf("invite is no longer available")
+	}
+	return &member, nil
+}
+
+func (u *crewInviteUseCase) Accept(inviteID uuid.UUID, userID int) (*entity_crew.CrewMember, error) {
+	invite, err := u.repoInvite.FindById(inviteID)
+	if err != nil || invite.InviteeID == nil || *invite.InviteeID != userID {
+		return nil, ErrInviteNotFound
+	}
+	return u.join(invite, userID)
+}
+
+func (u *crewInviteUseCase) AcceptCode(code string, userID int) (*entity_crew.CrewMember, error) {
+	invite, err := u.repoInvite.FindByCode(strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, ErrInviteNotFound
+	}
+	return u.join(invite, userID)
+}
+
+func (u *crewInviteUseCase) Decline(inviteID uuid.UUID, userID int) error {
+	invite, err := u.repoInvite.FindById(inviteID)
+	if err != nil || invite.InviteeID == nil || *invite.InviteeID != userID {
+		return ErrInviteNotFound
+	}
+	u.refreshStatus(invite)
+	if invite.Status != entity_crew.InviteStatusPending {
+		return fmt.Errorf("invite is %s", invite.Status)
+	}
+
+	now := time.Now()
+	invite.Status = entity_crew.InviteStatusDeclined
+	invite.RespondedAt = &now
+	invite.UpdatedAt = now
+	if err := u.repoInvite.Update(invite); err != nil {
+		return fmt.Errorf("could not decline invite")
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeCrewInviteRepository keeps invites in memory. Redeem counts the use
+// and adds the member as the conditional update of the database does.
+type fakeCrewInviteRepository struct {
+	invites    map[uuid.UUID]*entity_crew.CrewInvite
+	repoMember *fakeCrewMemberRepository
+}
+
+func (r *fakeCrewInviteRepository) Create(invite *entity_crew.CrewInvite) error {
+	id := uuid.New()
+	invite.ID = &id
+	copied := *invite
+	r.invites[id] = &copied
+	return nil
+}
+
+func (r *fakeCrewInviteRepository) FindById(id uuid.UUID) (*entity_crew.CrewInvite, error) {
+	invite, ok := r.invites[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *invite
+	return &copied, nil
+}
+
+func (r *fakeCrewInviteRepository) FindByCode(code string) (*entity_crew.CrewInvite, error) {
+	for id, invite := range r.invites {
+		if invite.Code != nil && *invite.Code == code {
+			return r.FindById(id)
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeCrewInviteRepository) FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewInvite, error) {
+	invites := []*entity_crew.CrewInvite{}
+	for id, invite := range r.invites {
+		if *invite.CrewID == crewID {
+			copied, _ := r.FindById(id)
+			invites = append(invites, copied)
+		}
+	}
+	return invites, nil
+}
+
+func (r *fakeCrewInviteRepository) FindAllPendingByInvitee(inviteeID int) ([]*entity_crew.CrewInvite, error) {
+	invites := []*entity_crew.CrewInvite{}
+	for id, invite := range r.invites {
+		if invite.InviteeID != nil && *invite.InviteeID == inviteeID && invite.Status == entity_crew.InviteStatusPending {
+			copied, _ := r.FindById(id)
+			invites = append(invites, copied)
+		}
+	}
+	return invites, nil
+}
+
+func (r *fakeCrewInviteRepository) FindPendingByCrewAndInvitee(crewID uuid.UUID, inviteeID int) (*entity_crew.CrewInvite, error) {
+	invites, _ := r.FindAllPendingByInvitee(inviteeID)
+	for _, invite := range invites {
+		if *invite.CrewID == crewID {
+			return invite, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeCrewInviteRepository) Update(invite *entity_crew.CrewInvite) error {
+	copied := *invite
+	r.invites[*invite.ID] = &copied
+	return nil
+}
+
+func (r *fakeCrewInviteRepository) Redeem(inviteID uuid.UUID, member *entity_crew.CrewMember) (bool, error) {
+	invite, ok := r.invites[inviteID]
+	if !ok || invite.Status != entity_crew.InviteStatusPending || (invite.MaxUses > 0 && invite.Uses >= invite.MaxUses) {
+		return false, nil
+	}
+	invite.Uses++
+	if invite.Code == nil || (invite.MaxUses > 0 && invite.Uses >= invite.MaxUses) {
+		invite.Status = entity_crew.InviteStatusAccepted
+	}
+	return true, r.repoMember.Create(member)
+}
+
+func TestCreateInvite(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int
+		maxUses int
+		wantErr bool
+	}{
+		{name: "admin creates a code invite", userID: userAdmin, maxUses: 3},
+		{name: "owner creates an unlimited code invite", userID: userOwner},
+		{name: "member can not invite", userID: userMember, wantErr: true},
+		{name: "outsider can not invite", userID: userOutsider, wantErr: true},
+		{name: "max uses must not be negative", userID: userOwner, maxUses: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, repoCrew, repoMember, repoUser := newCrewFixture()
+			repoInvite := &fakeCrewInviteRepository{invites: map[uuid.UUID]*entity_crew.CrewInvite{}, repoMember: repoMember}
+			usecase := NewCrewInviteUseCase(repoCrew, repoMember, repoInvite, repoUser)
+
+			invite, err := usecase.CreateCodeInvite(*crew.ID, tt.userID, 0, tt.maxUses)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CreateCodeInvite() = %v, want an error", invite)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreateCodeInvite() error = %v", err)
+			}
+			if invite.Code == nil || len(*invite.Code) != 10 {
+				t.Errorf("code = %v, want 10 characters", invite.Code)
+			}
+			if invite.MaxUses != tt.maxUses || invite.Status != entity_crew.InviteStatusPending {
+				t.Errorf("invite = %d uses %s, want %d uses pending", invite.MaxUses, invite.Status, tt.maxUses)
+			}
+			if expiresIn := time.Until(*invite.ExpiresAt); expiresIn <= DefaultInviteExpiration-time.Minute || expiresIn > DefaultInviteExpiration {
+				t.Errorf("invite expires in %v, want %v", expiresIn, DefaultInviteExpiration)
+			}
+		})
+	}
+}
+
+func TestRedeemCodeInvite(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		invite     entity_crew.CrewInvite
+		joiners    []int
+		wantJoined []int
+		wantUses   int
+		wantStatus string
+	}{
+		{
+			name:       "limited invite lets in as many users as its uses",
+			invite:     entity_crew.CrewInvite{MaxUses: 2, ExpiresAt: &future},
+			joiners:    []int{userOutsider, userOutsider + 1, userOutsider + 2},
+			wantJoined: []int{userOutsider, userOutsider + 1},
+			wantUses:   2,
+			wantStatus: entity_crew.InviteStatusAccepted,
+		},
+		{
+			name:       "unlimited invite stays pending",
+			invite:     entity_crew.CrewInvite{ExpiresAt: &future},
+			joiners:    []int{userOutsider, userOutsider + 1, userOutsider + 2},
+			wantJoined: []int{userOutsider, userOutsider + 1, userOutsider + 2},
+			wantUses:   3,
+			wantStatus: entity_crew.InviteStatusPending,
+		},
+		{
+			name:       "expired invite lets nobody in and is marked expired",
+			invite:     entity_crew.CrewInvite{ExpiresAt: &past},
+			joiners:    []int{userOutsider},
+			wantStatus: entity_crew.InviteStatusExpired,
+		},
+		{
+			name:       "revoked invite lets nobody in",
+			invite:     entity_crew.CrewInvite{Status: entity_crew.InviteStatusRevoked, ExpiresAt: &future},
+			joiners:    []int{userOutsider},
+			wantStatus: entity_crew.InviteStatusRevoked,
+		},
+		{
+			name:       "members do not use up the invite",
+			invite:     entity_crew.CrewInvite{MaxUses: 1, ExpiresAt: &future},
+			joiners:    []int{userMember, userOutsider},
+			wantJoined: []int{userOutsider},
+			wantUses:   1,
+			wantStatus: entity_crew.InviteStatusAccepted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, repoCrew, repoMember, repoUser := newCrewFixture()
+			repoInvite := &fakeCrewInviteRepository{invites: map[uuid.UUID]*entity_crew.CrewInvite{}, repoMember: repoMember}
+			usecase := NewCrewInviteUseCase(repoCrew, repoMember, repoInvite, repoUser)
+
+			code := "ABCDE12345"
+			invite := tt.invite
+			invite.CrewID, invite.Code, invite.IssuedByID = crew.ID, &code, userOwner
+			if invite.Status == "" {
+				invite.Status = entity_crew.InviteStatusPending
+			}
+			_ = repoInvite.Create(&invite)
+
+			joined := []int{}
+			for _, userID := range tt.joiners {
+				// Codes are typed by hand, in any case and with spaces around
+				if member, err := usecase.AcceptCode(" abcde12345 ", userID); err == nil {
+					if member.Role != entity_crew.CREW_ROLE_MEMBER {
+						t.Errorf("user %d joined as %q, want %q", userID, member.Role, entity_crew.CREW_ROLE_MEMBER)
+					}
+					joined = append(joined, userID)
+				}
+			}
+
+			if len(joined) != len(tt.wantJoined) {
+				t.Fatalf("joined = %v, want %v", joined, tt.wantJoined)
+			}
+			for i := range joined {
+				if joined[i] != tt.wantJoined[i] {
+					t.Errorf("joined = %v, want %v", joined, tt.wantJoined)
+				}
+			}
+			stored := repoInvite.invites[*invite.ID]
+			if stored.Uses != tt.wantUses || stored.Status != tt.wantStatus {
+				t.Errorf("invite = %d uses %s, want %d uses %s", stored.Uses, stored.Status, tt.wantUses, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAcceptEmailInvite(t *testing.T) {
+	crew, repoCrew, repoMember, repoUser := newCrewFixture()
+	repoUser.users[userOutsider].Email = "outsider@example.com"
+	repoInvite := &fakeCrewInviteRepository{invites: map[uuid.UUID]*entity_crew.CrewInvite{}, repoMember: repoMember}
+	usecase := NewCrewInviteUseCase(repoCrew, repoMember, repoInvite, repoUser)
+
+	invite, err := usecase.CreateEmailInvite(*crew.ID, userAdmin, " outsider@example.com ", 0)
+	if err != nil {
+		t.Fatalf("CreateEmailInvite() error = %v", err)
+	}
+	if _, err := usecase.CreateEmailInvite(*crew.ID, userAdmin, "outsider@example.com", 0); err == nil {
+		t.Errorf("second CreateEmailInvite() error = nil, want the pending invite error")
+	}
+	if _, err := usecase.Accept(*invite.ID, userOtherMember); !errors.Is(err, ErrInviteNotFound) {
+		t.Errorf("Accept() by another user error = %v, want %v", err, ErrInviteNotFound)
+	}
+	if _, err := usecase.Accept(*invite.ID, userOutsider); err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	if _, err := usecase.Accept(*invite.ID, userOutsider); err == nil || !strings.Contains(err.Error(), entity_crew.InviteStatusAccepted) {
+		t.Errorf("second Accept() error = %v, want the invite to be accepted", err)
+	}
+	if status := repoInvite.invites[*invite.ID].Status; status != entity_crew.InviteStatusAccepted {
+		t.Errorf("status = %s, want %s", status, entity_crew.InviteStatusAccepted)
+	}
+	if _, err := repoMember.FindByCrewAndUser(*crew.ID, userOutsider); err != nil {
+		t.Errorf("invitee is not a member: %v", err)
+	}
+}
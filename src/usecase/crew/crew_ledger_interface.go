@@ -0,0 +1,24 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+type IRepositoryCrewPayment interface {
+	Create(payment *entity_crew.CrewPayment) error
+	FindByIdAndCrew(id uuid.UUID, crewID uuid.UUID) (*entity_crew.CrewPayment, error)
+	FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewPayment, error)
+	Update(payment *entity_crew.CrewPayment) error
+}
+
+type IUseCaseCrewLedger interface {
+	GetBalances(crewID uuid.UUID, userID int) ([]*entity_crew.CrewBalance, error)
+	SettleUp(crewID uuid.UUID, userID int) ([]*entity_crew.SettlementTransfer, error)
+	GetPixCharge(crewID uuid.UUID, userID int, toUserID int) (*entity_crew.PixCharge, error)
+	GetPayments(crewID uuid.UUID, userID int) ([]*entity_crew.CrewPayment, error)
+	RecordPayment(payment *entity_crew.CrewPayment, crewID uuid.UUID, userID int) error
+	ConfirmPayment(id uuid.UUID, crewID uuid.UUID, userID int) error
+	RejectPayment(id uuid.UUID, crewID uuid.UUID, userID int) error
+}
@@ -0,0 +1,248 @@
+package usecase_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	usecase_accounts "app/usecase/accounts"
+	"app/utils/pix"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPaymentNotFound = errors.New("payment not found")
+
+type crewLedgerUseCase struct {
+	repoCrew    IRepositoryCrew
+	repoMember  IRepositoryCrewMember
+	repoExpense IRepositorySessionExpense
+	repoPayment IRepositoryCrewPayment
+	repoUser    usecase_accounts.IRepositoryUser
+	repoPix     usecase_accounts.IRepositoryUserPix
+	pixCity     string
+}
+
+func NewCrewLedgerUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoExpense IRepositorySessionExpense, repoPayment IRepositoryCrewPayment, repoUser usecase_accounts.IRepositoryUser, repoPix usecase_accounts.IRepositoryUserPix, pixCity string) IUseCaseCrewLedger {
+	return &crewLedgerUseCase{
+		repoCrew:    repoCrew,
+		repoMember:  repoMember,
+		repoExpense: repoExpense,
+		repoPayment: repoPayment,
+		repoUser:    repoUser,
+		repoPix:     repoPix,
+		pixCity:     pixCity,
+	}
+}
+
+// loadLedger returns the balance of every current member, plus former
+// members who still owe or are owed money, and the users they refer to.
+func (u *crewLedgerUseCase) loadLedger(crewID uuid.UUID, userID int) (map[int]int64, map[int]*entity_accounts.User, error) {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	expenses, err := u.repoExpense.FindAllByCrew(crewID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load expenses")
+	}
+	payments, err := u.repoPayment.FindAllByCrew(crewID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load payments")
+	}
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load crew members")
+	}
+
+	balances := computeBalances(expenses, payments)
+	users := map[int]*entity_accounts.User{}
+	for _, member := range members {
+		users[member.UserID] = member.User
+		if _, ok := balances[member.UserID]; !ok {
+			balances[member.UserID] = 0
+		}
+	}
+	for id := range balances {
+		if users[id] == nil {
+			if user, err := u.repoUser.FindById(id); err == nil {
+				users[id] = user
+			}
+		}
+	}
+	return balances, users, nil
+}
+
+func (u *crewLedgerUseCase) GetBalances(crewID uuid.UUID, userID int) ([]*entity_crew.CrewBalance, error) {
+	balances, users, err := u.loadLedger(crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*entity_crew.CrewBalance{}
+	for id, balance := range balances {
+		result = append(result, &entity_crew.CrewBalance{User: users[id], BalanceCents: balance})
+	}
+	sort.Slice(result, func(a, b int) bool { return result[a].BalanceCents > result[b].BalanceCents })
+	return result, nil
+}
+
+func (u *crewLedgerUseCase) SettleUp(crewID uuid.UUID, userID int) ([]*entity_crew.SettlementTransfer, error) {
+	balances, users, err := u.loadLedger(crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*entity_crew.SettlementTransfer{}
+	for _, t := range settleUp(balances) {
+		result = append(result, &entity_crew.SettlementTransfer{
+			From:        users[t.from],
+			To:          users[t.to],
+			AmountCents: t.amount,
+			PixKey:      payeePixKey(u.repoPix, t.to),
+		})
+	}
+	return result, nil
+}
+
+// GetPixCharge builds the BR Code the user can pay to settle what the
+// settle-up plan says they owe toUserID.
+func (u *crewLedgerUseCase) GetPixCharge(crewID uuid.UUID, userID int, toUserID int) (*entity_crew.PixCharge, error) {
+	balances, users, err := u.loadLedger(crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var amount int64
+	for _, t := range settleUp(balances) {
+		if t.from == userID && t.to == toUserID {
+			amount = t.amount
+			break
+		}
+	}
+	if amount == 0 {
+		return nil, fmt.Errorf("you have no pending debt with this member")
+	}
+
+	pixKey := payeePixKey(u.repoPix, toUserID)
+	if pixKey == nil {
+		return nil, fmt.Errorf("the creditor has no pix key registered")
+	}
+
+	creditor := users[toUserID]
+	name := "Movie Friends"
+	if creditor != nil && creditor.Name != "" {
+		name = creditor.Name
+	}
+
+	txID := pixTxID(crewID, userID, toUserID, amount)
+	payload, err := pix.BuildPayload(pix.Payload{
+		Key:          *pixKey,
+		MerchantName: name,
+		MerchantCity: u.pixCity,
+		AmountCents:  amount,
+		TxID:         txID,
+		Description:  "Movie Friends",
+	})
+	if err != nil {
+		return nil, err
+	}
+	png, err := pix.QRCodePNG(payload, 256)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate qr code")
+	}
+
+	return &entity_crew.PixCharge{
+		From:        users[userID],
+		To:          creditor,
+		AmountCents: amount,
+		PixKey:      *pixKey,
+		TxID:        txID,
+		Payload:     payload,
+		QRCodePNG:   png,
+	}, nil
+}
+
+// pixTxID identifies a settle-up transfer in the bank statement of the
+// payee: the same debt always gets the same txid, and different payers or
+// amounts get different ones. It is 25 letters and digits, the longest txid
+// a BR Code carries.
+func pixTxID(crewID uuid.UUID, fromUserID, toUserID int, amountCents int64) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d:%d:%d", crewID, fromUserID, toUserID, amountCents)))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))[:25]
+}
+
+func (u *crewLedgerUseCase) GetPayments(crewID uuid.UUID, userID int) ([]*entity_crew.CrewPayment, error) {
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID); err != nil {
+		return nil, err
+	}
+	return u.repoPayment.FindAllByCrew(crewID)
+}
+
+func (u *crewLedgerUseCase) RecordPayment(payment *entity_crew.CrewPayment, crewID uuid.UUID, userID int) error {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return err
+	}
+	if payment.AmountCents <= 0 {
+		return fmt.Errorf("amount_cents must be positive")
+	}
+	if payment.ToUserID == userID {
+		return fmt.Errorf("cannot pay yourself")
+	}
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, payment.ToUserID); err != nil {
+		return fmt.Errorf("user %d is not a member of the crew", payment.ToUserID)
+	}
+
+	payment.CrewID = crew.ID
+	payment.FromUserID = userID
+	payment.Status = entity_crew.PaymentStatusPaid
+
+	if err := u.repoPayment.Create(payment); err != nil {
+		return fmt.Errorf("could not record payment")
+	}
+	return nil
+}
+
+// reviewPayment lets the creditor confirm or reject a payment marked as paid.
+func (u *crewLedgerUseCase) reviewPayment(id uuid.UUID, crewID uuid.UUID, userID int, status string) error {
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID); err != nil {
+		return err
+	}
+
+	payment, err := u.repoPayment.FindByIdAndCrew(id, crewID)
+	if err != nil {
+		return ErrPaymentNotFound
+	}
+	if payment.ToUserID != userID {
+		return ErrCrewForbidden
+	}
+	if payment.Status != entity_crew.PaymentStatusPaid {
+		return fmt.Errorf("payment is already %s", payment.Status)
+	}
+
+	now := time.Now()
+	payment.Status = status
+	payment.UpdatedAt = now
+	if status == entity_crew.PaymentStatusConfirmed {
+		payment.ConfirmedAt = &now
+	}
+	if err := u.repoPayment.Update(payment); err != nil {
+		return fmt.Errorf("could not update payment")
+	}
+	return nil
+}
+
+func (u *crewLedgerUseCase) ConfirmPayment(id uuid.UUID, crewID uuid.UUID, userID int) error {
+	return u.reviewPayment(id, crewID, userID, entity_crew.PaymentStatusConfirmed)
+}
+
+func (u *crewLedgerUseCase) RejectPayment(id uuid.UUID, crewID uuid.UUID, userID int) error {
+	return u.reviewPayment(id, crewID, userID, entity_crew.PaymentStatusRejected)
+}
@@ -0,0 +1,33 @@
+package usecase_crew
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPixTxID(t *testing.T) {
+	crewID := uuid.MustParse("7bcdd3d8-10b4-4d57-a736-7a75a69fb4de")
+	otherCrewID := uuid.MustParse("0f6a2b1c-3d4e-4f50-8a6b-7c8d9e0f1a2b")
+	txID := pixTxID(crewID, 1, 2, 8000)
+
+	if !regexp.MustCompile(`^[A-Za-z0-9]{25}$`).MatchString(txID) {
+		t.Fatalf("pixTxID() = %q, want 25 letters and digits", txID)
+	}
+	if again := pixTxID(crewID, 1, 2, 8000); again != txID {
+		t.Errorf("pixTxID() = %q then %q, want the same txid for the same transfer", txID, again)
+	}
+	others := map[string]string{
+		"other crew":   pixTxID(otherCrewID, 1, 2, 8000),
+		"other payer":  pixTxID(crewID, 3, 2, 8000),
+		"other payee":  pixTxID(crewID, 1, 3, 8000),
+		"other amount": pixTxID(crewID, 1, 2, 8001),
+		"swapped":      pixTxID(crewID, 2, 1, 8000),
+	}
+	for name, other := range others {
+		if other == txID {
+			t.Errorf("%s: pixTxID() = %q, want a different txid", name, other)
+		}
+	}
+}
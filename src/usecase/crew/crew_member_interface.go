@@ -0,0 +1,22 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+type IRepositoryCrewMember interface {
+	Create(member *entity_crew.CrewMember) error
+	FindByCrewAndUser(crewID uuid.UUID, userID int) (*entity_crew.CrewMember, error)
+	FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewMember, error)
+	Update(member *entity_crew.CrewMember) error
+	Delete(id uuid.UUID) error
+}
+
+type IUseCaseCrewMember interface {
+	Add(crewID uuid.UUID, userID int, memberUserID int, role string) (*entity_crew.CrewMember, error)
+	GetAll(crewID uuid.UUID, userID int) ([]*entity_crew.CrewMember, error)
+	UpdateRole(crewID uuid.UUID, userID int, memberUserID int, role string) error
+	Remove(crewID uuid.UUID, userID int, memberUserID int) error
+}
@@ -0,0 +1,141 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	usecase_accounts "app/usecase/accounts"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type crewMemberUseCase struct {
+	repoCrew   IRepositoryCrew
+	repoMember IRepositoryCrewMember
+	repoUser   usecase_accounts.IRepositoryUser
+}
+
+func NewCrewMemberUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoUser usecase_accounts.IRepositoryUser) IUseCaseCrewMember {
+	return &crewMemberUseCase{repoCrew: repoCrew, repoMember: repoMember, repoUser: repoUser}
+}
+
+// checkRoleChange validates that requester may give target the new role.
+// Only the owner can grant or revoke the admin role, and ownership itself
+// cannot be handed out through membership management.
+func checkRoleChange(requester *entity_crew.CrewMember, role string) error {
+	if !entity_crew.IsValidCrewRole(role) {
+		return fmt.Errorf("invalid role: must be '%s' or '%s'", entity_crew.CREW_ROLE_ADMIN, entity_crew.CREW_ROLE_MEMBER)
+	}
+	if role == entity_crew.CREW_ROLE_OWNER {
+		return fmt.Errorf("owner role cannot be assigned")
+	}
+	if role == entity_crew.CREW_ROLE_ADMIN && requester.Role != entity_crew.CREW_ROLE_OWNER {
+		return ErrCrewForbidden
+	}
+	return nil
+}
+
+func (u *crewMemberUseCase) Add(crewID uuid.UUID, userID int, memberUserID int, role string) (*entity_crew.CrewMember, error) {
+	crew, requester, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !requester.CanManageMembers() {
+		return nil, ErrCrewForbidden
+	}
+
+	if role == "" {
+		role = entity_crew.CREW_ROLE_MEMBER
+	}
+	if err := checkRoleChange(requester, role); err != nil {
+		return nil, err
+	}
+
+	user, err := u.repoUser.FindById(memberUserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, memberUserID); err == nil {
+		return nil, fmt.Errorf("user is already a member of the crew")
+	}
+
+	member := entity_crew.CrewMember{
+		CrewID: crew.ID,
+		UserID: user.ID,
+		Role:   role,
+	}
+	if err := u.repoMember.Create(&member); err != nil {
+		return nil, fmt.Errorf("could not add crew member")
+	}
+	member.User = user
+	return &member, nil
+}
+
+func (u *crewMemberUseCase) GetAll(crewID uuid.UUID, userID int) ([]*entity_crew.CrewMember, error) {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return findAllMembers(u.repoMember, crew)
+}
+
+func (u *crewMemberUseCase) UpdateRole(crewID uuid.UUID, userID int, memberUserID int, role string) error {
+	_, requester, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return err
+	}
+	if !requester.CanManageMembers() {
+		return ErrCrewForbidden
+	}
+	if err := checkRoleChange(requester, role); err != nil {
+		return err
+	}
+
+	target, err := u.repoMember.FindByCrewAndUser(crewID, memberUserID)
+	if err != nil {
+		return fmt.Errorf("crew member not found")
+	}
+	if target.Role == entity_crew.CREW_ROLE_OWNER {
+		return fmt.Errorf("the owner role cannot be changed")
+	}
+	if target.Role == entity_crew.CREW_ROLE_ADMIN && requester.Role != entity_crew.CREW_ROLE_OWNER {
+		return ErrCrewForbidden
+	}
+
+	target.Role = role
+	target.UpdatedAt = time.Now()
+	if err := u.repoMember.Update(target); err != nil {
+		return fmt.Errorf("could not update crew member")
+	}
+	return nil
+}
+
+func (u *crewMemberUseCase) Remove(crewID uuid.UUID, userID int, memberUserID int) error {
+	_, requester, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return err
+	}
+
+	target, err := u.repoMember.FindByCrewAndUser(crewID, memberUserID)
+	if err != nil {
+		return fmt.Errorf("crew member not found")
+	}
+	if target.Role == entity_crew.CREW_ROLE_OWNER {
+		return fmt.Errorf("the crew owner cannot be removed")
+	}
+
+	// Any member may leave the crew; removing someone else requires management rights.
+	if memberUserID != userID {
+		if !requester.CanManageMembers() {
+			return ErrCrewForbidden
+		}
+		if target.Role == entity_crew.CREW_ROLE_ADMIN && requester.Role != entity_crew.CREW_ROLE_OWNER {
+			return ErrCrewForbidden
+		}
+	}
+
+	if err := u.repoMember.Delete(*target.ID); err != nil {
+		return fmt.Errorf("could not remove crew member")
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCrewMemberRoles(t *testing.T) {
+	// errInvalid stands for the validation errors, which have no sentinel
+	errInvalid := errors.New("invalid")
+
+	tests := []struct {
+		name     string
+		userID   int
+		run      func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error
+		wantErr  error
+		wantRole map[int]string // Roles after the change, "" for users no longer members
+	}{
+		{
+			name:   "member can not add members",
+			userID: userMember,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				_, err := usecase.Add(crewID, userID, userOutsider, "")
+				return err
+			},
+			wantErr: ErrCrewForbidden,
+		},
+		{
+			name:   "admin adds a member",
+			userID: userAdmin,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				_, err := usecase.Add(crewID, userID, userOutsider, "")
+				return err
+			},
+			wantRole: map[int]string{userOutsider: entity_crew.CREW_ROLE_MEMBER},
+		},
+		{
+			name:   "admin can not add an admin",
+			userID: userAdmin,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				_, err := usecase.Add(crewID, userID, userOutsider, entity_crew.CREW_ROLE_ADMIN)
+				return err
+			},
+			wantErr: ErrCrewForbidden,
+		},
+		{
+			name:   "owner adds an admin",
+			userID: userOwner,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				_, err := usecase.Add(crewID, userID, userOutsider, entity_crew.CREW_ROLE_ADMIN)
+				return err
+			},
+			wantRole: map[int]string{userOutsider: entity_crew.CREW_ROLE_ADMIN},
+		},
+		{
+			name:   "owner role can not be given",
+			userID: userOwner,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.UpdateRole(crewID, userID, userMember, entity_crew.CREW_ROLE_OWNER)
+			},
+			wantErr:  errInvalid,
+			wantRole: map[int]string{userMember: entity_crew.CREW_ROLE_MEMBER},
+		},
+		{
+			name:   "admin can not promote to admin",
+			userID: userAdmin,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.UpdateRole(crewID, userID, userMember, entity_crew.CREW_ROLE_ADMIN)
+			},
+			wantErr:  ErrCrewForbidden,
+			wantRole: map[int]string{userMember: entity_crew.CREW_ROLE_MEMBER},
+		},
+		{
+			name:   "owner promotes a member to admin",
+			userID: userOwner,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.UpdateRole(crewID, userID, userMember, entity_crew.CREW_ROLE_ADMIN)
+			},
+			wantRole: map[int]string{userMember: entity_crew.CREW_ROLE_ADMIN},
+		},
+		{
+			name:   "owner demotes an admin",
+			userID: userOwner,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.UpdateRole(crewID, userID, userAdmin, entity_crew.CREW_ROLE_MEMBER)
+			},
+			wantRole: map[int]string{userAdmin: entity_crew.CREW_ROLE_MEMBER},
+		},
+		{
+			name:   "owner role can not be changed",
+			userID: userOwner,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.UpdateRole(crewID, userID, userOwner, entity_crew.CREW_ROLE_MEMBER)
+			},
+			wantErr:  errInvalid,
+			wantRole: map[int]string{userOwner: entity_crew.CREW_ROLE_OWNER},
+		},
+		{
+			name:   "member leaves the crew",
+			userID: userMember,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.Remove(crewID, userID, userMember)
+			},
+			wantRole: map[int]string{userMember: ""},
+		},
+		{
+			name:   "member can not remove another member",
+			userID: userMember,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.Remove(crewID, userID, userOtherMember)
+			},
+			wantErr:  ErrCrewForbidden,
+			wantRole: map[int]string{userOtherMember: entity_crew.CREW_ROLE_MEMBER},
+		},
+		{
+			name:   "admin removes a member",
+			userID: userAdmin,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.Remove(crewID, userID, userMember)
+			},
+			wantRole: map[int]string{userMember: ""},
+		},
+		{
+			name:   "admin can not remove the owner",
+			userID: userAdmin,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.Remove(crewID, userID, userOwner)
+			},
+			wantErr:  errInvalid,
+			wantRole: map[int]string{userOwner: entity_crew.CREW_ROLE_OWNER},
+		},
+		{
+			name:   "owner removes an admin",
+			userID: userOwner,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				return usecase.Remove(crewID, userID, userAdmin)
+			},
+			wantRole: map[int]string{userAdmin: ""},
+		},
+		{
+			name:   "outsider can not list the members",
+			userID: userOutsider,
+			run: func(usecase IUseCaseCrewMember, crewID uuid.UUID, userID int) error {
+				_, err := usecase.GetAll(crewID, userID)
+				return err
+			},
+			wantErr: ErrCrewNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, repoCrew, repoMember, repoUser := newCrewFixture()
+			usecase := NewCrewMemberUseCase(repoCrew, repoMember, repoUser)
+
+			err := tt.run(usecase, *crew.ID, tt.userID)
+			switch {
+			case tt.wantErr == errInvalid:
+				if err == nil || errors.Is(err, ErrCrewForbidden) || errors.Is(err, ErrCrewNotFound) {
+					t.Errorf("error = %v, want a validation error", err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			for userID, want := range tt.wantRole {
+				got := ""
+				if member, err := repoMember.FindByCrewAndUser(*crew.ID, userID); err == nil {
+					got = member.Role
+				}
+				if got != want {
+					t.Errorf("role of user %d = %q, want %q", userID, got, want)
+				}
+			}
+		})
+	}
+}
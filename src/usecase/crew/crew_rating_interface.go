@@ -0,0 +1,11 @@
+package usecase_crew
+
+import (
+	entity_movies "app/entity/movies"
+
+	"github.com/google/uuid"
+)
+
+type IUseCaseCrewRating interface {
+	GetMovieRatings(crewID uuid.UUID, movieID uuid.UUID, userID int) (*entity_movies.MovieRatings, error)
+}
@@ -0,0 +1,43 @@
+package usecase_crew
+
+import (
+	entity_movies "app/entity/movies"
+	usecase_movies "app/usecase/movies"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+type crewRatingUseCase struct {
+	repoCrew       IRepositoryCrew
+	repoMember     IRepositoryCrewMember
+	usecaseHistory usecase_movies.IUseCaseWatchHistory
+}
+
+func NewCrewRatingUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, usecaseHistory usecase_movies.IUseCaseWatchHistory) IUseCaseCrewRating {
+	return &crewRatingUseCase{
+		repoCrew:       repoCrew,
+		repoMember:     repoMember,
+		usecaseHistory: usecaseHistory,
+	}
+}
+
+// GetMovieRatings aggregates the watch history of the crew members for a
+// movie, so the crew can see who already watched it and what they thought.
+func (u *crewRatingUseCase) GetMovieRatings(crewID uuid.UUID, movieID uuid.UUID, userID int) (*entity_movies.MovieRatings, error) {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return nil, fmt.Errorf("could not load crew members")
+	}
+	userIDs := []int{}
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+
+	return u.usecaseHistory.GetMovieRatings(movieID, userIDs)
+}
@@ -0,0 +1,16 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultRecommendationLimit = 10
+	MaxRecommendationLimit     = 50
+)
+
+type IUseCaseCrewRecommendation interface {
+	Recommend(crewID uuid.UUID, userID int, sessionID *uuid.UUID, limit int) ([]*entity_crew.MovieRecommendation, error)
+}
@@ -0,0 +1,144 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	usecase_movies "app/usecase/movies"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const (
+	similarUsersLimit   = 100 // Other users whose ratings are compared with the crew's
+	genreCandidateLimit = 200 // Catalog movies considered for the crew's favorite genres
+	favoriteGenreLimit  = 5
+)
+
+type crewRecommendationUseCase struct {
+	repoCrew       IRepositoryCrew
+	repoMember     IRepositoryCrewMember
+	repoSession    IRepositoryMovieSession
+	repoRSVP       IRepositorySessionRSVP
+	repoWatchEntry usecase_movies.IRepositoryWatchEntry
+	repoMovie      usecase_movies.IRepositoryMovie
+	usecaseHistory usecase_movies.IUseCaseWatchHistory
+}
+
+func NewCrewRecommendationUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoSession IRepositoryMovieSession, repoRSVP IRepositorySessionRSVP, repoWatchEntry usecase_movies.IRepositoryWatchEntry, repoMovie usecase_movies.IRepositoryMovie, usecaseHistory usecase_movies.IUseCaseWatchHistory) IUseCaseCrewRecommendation {
+	return &crewRecommendationUseCase{
+		repoCrew:       repoCrew,
+		repoMember:     repoMember,
+		repoSession:    repoSession,
+		repoRSVP:       repoRSVP,
+		repoWatchEntry: repoWatchEntry,
+		repoMovie:      repoMovie,
+		usecaseHistory: usecaseHistory,
+	}
+}
+
+// attendees returns the members going to the session, or every member when
+// no session is given or nobody answered going yet.
+func (u *crewRecommendationUseCase) attendees(crewID uuid.UUID, sessionID *uuid.UUID, members []*entity_crew.CrewMember) (map[int]bool, error) {
+	isMember := map[int]bool{}
+	for _, member := range members {
+		isMember[member.UserID] = true
+	}
+
+	attendees := map[int]bool{}
+	if sessionID != nil {
+		if _, err := u.repoSession.FindByIdAndCrew(*sessionID, crewID); err != nil {
+			return nil, ErrSessionNotFound
+		}
+		rsvps, err := u.repoRSVP.FindAllBySession(*sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not load RSVPs")
+		}
+		for _, rsvp := range rsvps {
+			if rsvp.Status == entity_crew.RSVPStatusGoing && isMember[rsvp.UserID] {
+				attendees[rsvp.UserID] = true
+			}
+		}
+	}
+	if len(attendees) == 0 {
+		attendees = isMember
+	}
+	return attendees, nil
+}
+
+// Recommend suggests catalog movies the attending members have not watched,
+// from the ratings of the crew, of users with similar taste and the genres
+// the crew likes.
+func (u *crewRecommendationUseCase) Recommend(crewID uuid.UUID, userID int, sessionID *uuid.UUID, limit int) ([]*entity_crew.MovieRecommendation, error) {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 {
+		limit = DefaultRecommendationLimit
+	}
+	if limit > MaxRecommendationLimit {
+		return nil, fmt.Errorf("limit must be at most %d", MaxRecommendationLimit)
+	}
+
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return nil, fmt.Errorf("could not load crew members")
+	}
+	attendees, err := u.attendees(crewID, sessionID, members)
+	if err != nil {
+		return nil, err
+	}
+	memberIDs := []int{}
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.UserID)
+	}
+	if err := u.usecaseHistory.Sync(memberIDs); err != nil {
+		return nil, err
+	}
+
+	entries, err := u.repoWatchEntry.FindAllByUsers(memberIDs)
+	if err != nil {
+		return nil, fmt.Errorf("could not load watch history")
+	}
+	excluded := map[uuid.UUID]bool{}
+	ratedIDs := []uuid.UUID{}
+	for _, entry := range entries {
+		if attendees[entry.UserID] {
+			excluded[*entry.MovieID] = true
+		}
+		if entry.Rating != nil {
+			ratedIDs = append(ratedIDs, *entry.MovieID)
+		}
+	}
+
+	if len(ratedIDs) > 0 {
+		similarIDs, err := u.repoWatchEntry.FindSimilarUsers(ratedIDs, memberIDs, similarUsersLimit)
+		if err != nil {
+			return nil, fmt.Errorf("could not load similar users")
+		}
+		if len(similarIDs) > 0 {
+			similarEntries, err := u.repoWatchEntry.FindAllByUsers(similarIDs)
+			if err != nil {
+				return nil, fmt.Errorf("could not load watch history")
+			}
+			entries = append(entries, similarEntries...)
+		}
+	}
+
+	movies := map[uuid.UUID]*entity_movies.Movie{}
+	for _, entry := range entries {
+		movies[*entry.MovieID] = entry.Movie
+	}
+	genres := favoriteGenres(crewGenreAffinity(members, buildProfiles(entries), movies), favoriteGenreLimit)
+	excludedIDs := []uuid.UUID{}
+	for movieID := range excluded {
+		excludedIDs = append(excludedIDs, movieID)
+	}
+	candidates, err := u.repoMovie.FindAllByGenres(genres, excludedIDs, genreCandidateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("could not load movies")
+	}
+
+	return recommendMovies(members, entries, candidates, excluded, limit), nil
+}
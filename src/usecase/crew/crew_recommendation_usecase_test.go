@@ -0,0 +1,195 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	usecase_movies "app/usecase/movies"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeWatchEntryRepository keeps watch entries in memory. The methods the
+// recommendations do not use are left to the embedded nil interface.
+type fakeWatchEntryRepository struct {
+	usecase_movies.IRepositoryWatchEntry
+	entries []*entity_movies.WatchEntry
+}
+
+func (r *fakeWatchEntryRepository) FindAllByUsers(userIDs []int) ([]*entity_movies.WatchEntry, error) {
+	entries := []*entity_movies.WatchEntry{}
+	for _, entry := range r.entries {
+		for _, userID := range userIDs {
+			if entry.UserID == userID {
+				entries = append(entries, entry)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeWatchEntryRepository) FindSimilarUsers(movieIDs []uuid.UUID, excludeUserIDs []int, limit int) ([]int, error) {
+	excluded := map[int]bool{}
+	for _, userID := range excludeUserIDs {
+		excluded[userID] = true
+	}
+	userIDs := []int{}
+	for _, entry := range r.entries {
+		if excluded[entry.UserID] || entry.Rating == nil {
+			continue
+		}
+		for _, movieID := range movieIDs {
+			if *entry.MovieID == movieID && len(userIDs) < limit {
+				excluded[entry.UserID] = true
+				userIDs = append(userIDs, entry.UserID)
+				break
+			}
+		}
+	}
+	return userIDs, nil
+}
+
+// fakeMovieRepository holds the catalog.
+type fakeMovieRepository struct {
+	usecase_movies.IRepositoryMovie
+	movies []*entity_movies.Movie
+}
+
+func (r *fakeMovieRepository) FindAllByGenres(genres []string, excludeIDs []uuid.UUID, limit int) ([]*entity_movies.Movie, error) {
+	excluded, wanted := map[uuid.UUID]bool{}, map[string]bool{}
+	for _, id := range excludeIDs {
+		excluded[id] = true
+	}
+	for _, genre := range genres {
+		wanted[genre] = true
+	}
+	movies := []*entity_movies.Movie{}
+	for _, movie := range r.movies {
+		if excluded[*movie.ID] || len(movies) == limit {
+			continue
+		}
+		for _, genre := range movie.Genres {
+			if wanted[genre] {
+				movies = append(movies, movie)
+				break
+			}
+		}
+	}
+	return movies, nil
+}
+
+// fakeWatchHistoryUseCase has the history already in sync.
+type fakeWatchHistoryUseCase struct {
+	usecase_movies.IUseCaseWatchHistory
+}
+
+func (u *fakeWatchHistoryUseCase) Sync(userIDs []int) error {
+	return nil
+}
+
+// Users outside the crew: the first rates like the owner, the second the
+// opposite way
+const (
+	userLikeOwner = iota + 10
+	userUnlikeOwner
+)
+
+func newTestMovie(title string, genres ...string) *entity_movies.Movie {
+	id := uuid.New()
+	return &entity_movies.Movie{ID: &id, Title: title, Genres: genres}
+}
+
+func TestRecommend(t *testing.T) {
+	alien, notebook := newTestMovie("Alien", "Horror"), newTestMovie("The Notebook", "Romance")
+	heat, ronin := newTestMovie("Heat"), newTestMovie("Ronin")
+	thing, fargo, cats := newTestMovie("The Thing"), newTestMovie("Fargo"), newTestMovie("Cats")
+	halloween, casablanca := newTestMovie("Halloween", "Horror"), newTestMovie("Casablanca", "Romance")
+
+	entries := []*entity_movies.WatchEntry{}
+	rate := func(userID int, movie *entity_movies.Movie, rating float64) {
+		entries = append(entries, &entity_movies.WatchEntry{UserID: userID, Movie: movie, MovieID: movie.ID, Rating: &rating})
+	}
+	// The owner loves Alien and hates The Notebook, the admin loves Heat
+	rate(userOwner, alien, 5)
+	rate(userOwner, notebook, 1)
+	rate(userAdmin, heat, 5)
+	rate(userAdmin, ronin, 1)
+	rate(userLikeOwner, alien, 5)
+	rate(userLikeOwner, notebook, 1)
+	rate(userLikeOwner, thing, 5)
+	rate(userLikeOwner, fargo, 4)
+	rate(userLikeOwner, cats, 1)
+	rate(userUnlikeOwner, alien, 1)
+	rate(userUnlikeOwner, notebook, 5)
+	rate(userUnlikeOwner, thing, 1)
+	rate(userUnlikeOwner, cats, 5)
+
+	tests := []struct {
+		name  string
+		going []int // Members going to the session, nil for no session
+		want  []string
+	}{
+		{
+			name: "every member attends without a session",
+			want: []string{
+				"The Thing: Rated highly by people with similar taste to Ana",
+				"Fargo: Rated highly by people with similar taste to Ana",
+				"Halloween: Matches the crew's taste for Horror",
+			},
+		},
+		{
+			name:  "movies of members not going are not excluded",
+			going: []int{userOwner, userMember},
+			want: []string{
+				"Heat: Liked by Bruno",
+				"The Thing: Rated highly by people with similar taste to Ana",
+				"Fargo: Rated highly by people with similar taste to Ana",
+				"Halloween: Matches the crew's taste for Horror",
+			},
+		},
+		{
+			name:  "every member attends when nobody is going yet",
+			going: []int{},
+			want: []string{
+				"The Thing: Rated highly by people with similar taste to Ana",
+				"Fargo: Rated highly by people with similar taste to Ana",
+				"Halloween: Matches the crew's taste for Horror",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, repoCrew, repoMember, _ := newCrewFixture()
+			repoSession := &fakeMovieSessionRepository{sessions: map[uuid.UUID]*entity_crew.MovieSession{}}
+			repoRSVP := &fakeSessionRSVPRepository{}
+			repoWatchEntry := &fakeWatchEntryRepository{entries: entries}
+			repoMovie := &fakeMovieRepository{movies: []*entity_movies.Movie{alien, notebook, heat, halloween, casablanca}}
+			usecase := NewCrewRecommendationUseCase(repoCrew, repoMember, repoSession, repoRSVP, repoWatchEntry, repoMovie, &fakeWatchHistoryUseCase{})
+
+			var sessionID *uuid.UUID
+			if tt.going != nil {
+				session := &entity_crew.MovieSession{CrewID: crew.ID}
+				_ = repoSession.Create(session)
+				sessionID = session.ID
+				for _, userID := range tt.going {
+					_ = repoRSVP.Create(&entity_crew.SessionRSVP{SessionID: session.ID, UserID: userID, Status: entity_crew.RSVPStatusGoing})
+				}
+				_ = repoRSVP.Create(&entity_crew.SessionRSVP{SessionID: session.ID, UserID: userAdmin, Status: entity_crew.RSVPStatusNotGoing})
+			}
+
+			recommendations, err := usecase.Recommend(*crew.ID, userMember, sessionID, 0)
+			if err != nil {
+				t.Fatalf("Recommend() error = %v", err)
+			}
+			got := []string{}
+			for _, recommendation := range recommendations {
+				got = append(got, recommendation.Movie.Title+": "+recommendation.Reason)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("recommendations = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
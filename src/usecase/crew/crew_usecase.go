@@ -0,0 +1,130 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCrewNotFound  = errors.New("crew not found or access denied")
+	ErrCrewForbidden = errors.New("not allowed to perform this action on the crew")
+)
+
+type crewUseCase struct {
+	repo       IRepositoryCrew
+	repoMember IRepositoryCrewMember
+}
+
+func NewCrewUseCase(repo IRepositoryCrew, repoMember IRepositoryCrewMember) IUseCaseCrew {
+	return &crewUseCase{repo: repo, repoMember: repoMember}
+}
+
+// findMembership loads the crew and the membership of userID in it.
+// Owners of crews created before memberships existed have no crew_members
+// row, so they get a synthesized owner membership.
+func findMembership(repo IRepositoryCrew, repoMember IRepositoryCrewMember, crewID uuid.UUID, userID int) (*entity_crew.Crew, *entity_crew.CrewMember, error) {
+	crew, err := repo.FindById(crewID)
+	if err != nil {
+		return nil, nil, ErrCrewNotFound
+	}
+
+	member, err := repoMember.FindByCrewAndUser(crewID, userID)
+	if err != nil {
+		if crew.OwnerID != userID {
+			return nil, nil, ErrCrewNotFound
+		}
+		member = &entity_crew.CrewMember{CrewID: crew.ID, UserID: userID, Role: entity_crew.CREW_ROLE_OWNER}
+	}
+	return crew, member, nil
+}
+
+// findAllMembers lists the members of crew with their users preloaded,
+// including the owner of crews that predate memberships.
+func findAllMembers(repoMember IRepositoryCrewMember, crew *entity_crew.Crew) ([]*entity_crew.CrewMember, error) {
+	members, err := repoMember.FindAllByCrew(*crew.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.UserID == crew.OwnerID {
+			return members, nil
+		}
+	}
+	owner := &entity_crew.CrewMember{CrewID: crew.ID, User: crew.Owner, UserID: crew.OwnerID, Role: entity_crew.CREW_ROLE_OWNER}
+	return append([]*entity_crew.CrewMember{owner}, members...), nil
+}
+
+func (u *crewUseCase) Create(crew *entity_crew.Crew, ownerID int) error {
+	crew.OwnerID = ownerID
+
+	if err := u.repo.Create(crew); err != nil {
+		return fmt.Errorf("could not create crew")
+	}
+
+	owner := entity_crew.CrewMember{
+		CrewID: crew.ID,
+		UserID: ownerID,
+		Role:   entity_crew.CREW_ROLE_OWNER,
+	}
+	if err := u.repoMember.Create(&owner); err != nil {
+		_ = u.repo.Delete(*crew.ID)
+		return fmt.Errorf("could not create crew")
+	}
+	return nil
+}
+
+func (u *crewUseCase) GetById(id uuid.UUID, userID int) (*entity_crew.Crew, error) {
+	crew, _, err := findMembership(u.repo, u.repoMember, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return crew, nil
+}
+
+func (u *crewUseCase) GetAll(userID int) ([]*entity_crew.Crew, error) {
+	return u.repo.FindAllByMember(userID)
+}
+
+func (u *crewUseCase) Update(crew *entity_crew.Crew, userID int) error {
+	if crew.ID == nil {
+		return fmt.Errorf("crew id is required")
+	}
+
+	existing, member, err := findMembership(u.repo, u.repoMember, *crew.ID, userID)
+	if err != nil {
+		return err
+	}
+	if !member.CanManageMembers() {
+		return ErrCrewForbidden
+	}
+
+	existing.Name = crew.Name
+	existing.UpdatedAt = time.Now()
+	// Owner is preloaded; clear it so Save does not try to upsert the user.
+	existing.Owner = nil
+
+	if err := u.repo.Update(existing); err != nil {
+		return fmt.Errorf("could not update crew")
+	}
+	*crew = *existing
+	return nil
+}
+
+func (u *crewUseCase) Delete(id uuid.UUID, userID int) error {
+	_, member, err := findMembership(u.repo, u.repoMember, id, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role != entity_crew.CREW_ROLE_OWNER {
+		return ErrCrewForbidden
+	}
+
+	if err := u.repo.Delete(id); err != nil {
+		return fmt.Errorf("could not delete crew")
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+package usecase_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakeCrewRepository keeps crews in memory.
+type fakeCrewRepository struct {
+	crews map[uuid.UUID]*entity_crew.Crew
+}
+
+func (r *fakeCrewRepository) Create(crew *entity_crew.Crew) error {
+	id := uuid.New()
+	crew.ID = &id
+	r.crews[id] = crew
+	return nil
+}
+
+func (r *fakeCrewRepository) FindById(id uuid.UUID) (*entity_crew.Crew, error) {
+	crew, ok := r.crews[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *crew
+	return &copied, nil
+}
+
+func (r *fakeCrewRepository) FindAllByOwner(ownerID int) ([]*entity_crew.Crew, error) {
+	crews := []*entity_crew.Crew{}
+	for _, crew := range r.crews {
+		if crew.OwnerID == ownerID {
+			crews = append(crews, crew)
+		}
+	}
+	return crews, nil
+}
+
+// FindAllByMember only finds the crews owned by userID, since memberships
+// are kept by the member repository.
+func (r *fakeCrewRepository) FindAllByMember(userID int) ([]*entity_crew.Crew, error) {
+	return r.FindAllByOwner(userID)
+}
+
+func (r *fakeCrewRepository) Update(crew *entity_crew.Crew) error {
+	r.crews[*crew.ID] = crew
+	return nil
+}
+
+func (r *fakeCrewRepository) Delete(id uuid.UUID) error {
+	delete(r.crews, id)
+	return nil
+}
+
+// fakeCrewMemberRepository keeps memberships in memory.
+type fakeCrewMemberRepository struct {
+	members []*entity_crew.CrewMember
+}
+
+func (r *fakeCrewMemberRepository) Create(member *entity_crew.CrewMember) error {
+	id := uuid.New()
+	member.ID = &id
+	r.members = append(r.members, member)
+	return nil
+}
+
+func (r *fakeCrewMemberRepository) FindByCrewAndUser(crewID uuid.UUID, userID int) (*entity_crew.CrewMember, error) {
+	for _, member := range r.members {
+		if *member.CrewID == crewID && member.UserID == userID {
+			copied := *member
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeCrewMemberRepository) FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewMember, error) {
+	members := []*entity_crew.CrewMember{}
+	for _, member := range r.members {
+		if *member.CrewID == crewID {
+			copied := *member
+			members = append(members, &copied)
+		}
+	}
+	return members, nil
+}
+
+func (r *fakeCrewMemberRepository) Update(member *entity_crew.CrewMember) error {
+	for i := range r.members {
+		if *r.members[i].ID == *member.ID {
+			r.members[i] = member
+			return nil
+		}
+	}
+	return errors.New("record not found")
+}
+
+func (r *fakeCrewMemberRepository) Delete(id uuid.UUID) error {
+	for i := range r.members {
+		if *r.members[i].ID == id {
+			r.members = append(r.members[:i], r.members[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// fakeUserRepository keeps users in memory.
+type fakeUserRepository struct {
+	users map[int]*entity_accounts.User
+}
+
+func (r *fakeUserRepository) Create(user *entity_accounts.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) FindById(id int) (*entity_accounts.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return user, nil
+}
+
+func (r *fakeUserRepository) FindByEmail(email string) (*entity_accounts.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeUserRepository) FindByCalendarToken(token string) (*entity_accounts.User, error) {
+	return nil, errors.New("record not found")
+}
+
+func (r *fakeUserRepository) Update(user *entity_accounts.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+// Users of the crew fixture
+const (
+	userOwner = iota + 1
+	userAdmin
+	userMember
+	userOtherMember
+	userOutsider
+)
+
+// newCrewFixture returns a crew owned by userOwner with an admin and two
+// members, and the users of the fixture.
+func newCrewFixture() (*entity_crew.Crew, *fakeCrewRepository, *fakeCrewMemberRepository, *fakeUserRepository) {
+	id := uuid.New()
+	crew := &entity_crew.Crew{ID: &id, Name: "Friday movies", OwnerID: userOwner}
+	repoCrew := &fakeCrewRepository{crews: map[uuid.UUID]*entity_crew.Crew{id: crew}}
+	repoMember := &fakeCrewMemberRepository{}
+	repoUser := &fakeUserRepository{users: map[int]*entity_accounts.User{}}
+	names := map[int]string{userOwner: "Ana", userAdmin: "Bruno", userMember: "Carla", userOtherMember: "Davi", userOutsider: "Eva"}
+	roles := map[int]string{
+		userOwner:       entity_crew.CREW_ROLE_OWNER,
+		userAdmin:       entity_crew.CREW_ROLE_ADMIN,
+		userMember:      entity_crew.CREW_ROLE_MEMBER,
+		userOtherMember: entity_crew.CREW_ROLE_MEMBER,
+	}
+	for userID := userOwner; userID <= userOutsider; userID++ {
+		repoUser.users[userID] = &entity_accounts.User{ID: userID, Name: names[userID]}
+		if role, ok := roles[userID]; ok {
+			_ = repoMember.Create(&entity_crew.CrewMember{CrewID: crew.ID, User: repoUser.users[userID], UserID: userID, Role: role})
+		}
+	}
+	return crew, repoCrew, repoMember, repoUser
+}
+
+func TestCrewRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int
+		run     func(usecase IUseCaseCrew, crewID uuid.UUID, userID int) error
+		wantErr error
+	}{
+		{
+			name:   "member can read the crew",
+			userID: userMember,
+			run: func(usecase IUseCaseCrew, crewID uuid.UUID, userID int) error {
+				_, err := usecase.GetById(crewID, userID)
+				return err
+			},
+		},
+		{
+			name:   "outsider can not read the crew",
+			userID: userOutsider,
+			run: func(usecase IUseCaseCrew, crewID uuid.UUID, userID int) error {
+				_, err := usecase.GetById(crewID, userID)
+				return err
+			},
+			wantErr: ErrCrewNotFound,
+		},
+		{
+			name:   "admin can rename the crew",
+			userID: userAdmin,
+			run: func(usecase IUseCaseCrew, crewID uuid.UUID, userID int) error {
+				return usecase.Update(&entity_crew.Crew{ID: &crewID, Name: "Saturday movies"}, userID)
+			},
+		},
+		{
+			name:   "member can not rename the crew",
+			userID: userMember,
+			run: func(usecase IUseCaseCrew, crewID uuid.UUID, userID int) error {
+				return usecase.Update(&entity_crew.Crew{ID: &crewID, Name: "Saturday movies"}, userID)
+			},
+			wantErr: ErrCrewForbidden,
+		},
+		{
+			name:   "admin can not delete the crew",
+			userID: userAdmin,
+			run: func(usecase IUseCaseCrew, crewID uuid.UUID, userID int) error {
+				return usecase.Delete(crewID, userID)
+			},
+			wantErr: ErrCrewForbidden,
+		},
+		{
+			name:   "owner can delete the crew",
+			userID: userOwner,
+			run: func(usecase IUseCaseCrew, crewID uuid.UUID, userID int) error {
+				return usecase.Delete(crewID, userID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, repoCrew, repoMember, _ := newCrewFixture()
+			usecase := NewCrewUseCase(repoCrew, repoMember)
+
+			err := tt.run(usecase, *crew.ID, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFindMembershipOfLegacyOwner(t *testing.T) {
+	crew, repoCrew, _, _ := newCrewFixture()
+	// Crews created before memberships have no row for their owner
+	repoMember := &fakeCrewMemberRepository{}
+
+	_, member, err := findMembership(repoCrew, repoMember, *crew.ID, userOwner)
+	if err != nil {
+		t.Fatalf("findMembership() error = %v", err)
+	}
+	if member.Role != entity_crew.CREW_ROLE_OWNER {
+		t.Errorf("role = %q, want %q", member.Role, entity_crew.CREW_ROLE_OWNER)
+	}
+	members, err := findAllMembers(repoMember, crew)
+	if err != nil {
+		t.Fatalf("findAllMembers() error = %v", err)
+	}
+	if len(members) != 1 || members[0].UserID != userOwner {
+		t.Errorf("findAllMembers() = %v, want the owner", members)
+	}
+}
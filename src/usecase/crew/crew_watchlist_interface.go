@@ -0,0 +1,33 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+// Values of the status filter when listing a watchlist
+const (
+	WatchlistFilterPending = "pending"
+	WatchlistFilterWatched = "watched"
+	WatchlistFilterAll     = "all"
+)
+
+type IRepositoryCrewWatchlist interface {
+	Create(item *entity_crew.CrewWatchlistItem) error
+	FindByIdAndCrew(id uuid.UUID, crewID uuid.UUID) (*entity_crew.CrewWatchlistItem, error)
+	FindByCrewAndMovie(crewID uuid.UUID, movieID uuid.UUID) (*entity_crew.CrewWatchlistItem, error)
+	FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.CrewWatchlistItem, error)
+	Update(item *entity_crew.CrewWatchlistItem) error
+	UpdatePositions(items []*entity_crew.CrewWatchlistItem) error
+	IsInPoll(id uuid.UUID) (bool, error)
+	Delete(id uuid.UUID) error
+}
+
+type IUseCaseCrewWatchlist interface {
+	Add(item *entity_crew.CrewWatchlistItem, crewID uuid.UUID, userID int) error
+	GetAll(crewID uuid.UUID, userID int, status string) ([]*entity_crew.CrewWatchlistItem, error)
+	Move(id uuid.UUID, crewID uuid.UUID, userID int, position int) ([]*entity_crew.CrewWatchlistItem, error)
+	MarkWatched(id uuid.UUID, crewID uuid.UUID, userID int) (*entity_crew.CrewWatchlistItem, error)
+	Remove(id uuid.UUID, crewID uuid.UUID, userID int) error
+}
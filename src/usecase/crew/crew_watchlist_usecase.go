@@ -0,0 +1,215 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	usecase_movies "app/usecase/movies"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWatchlistItemNotFound = errors.New("watchlist item not found")
+	ErrWatchlistItemInPoll   = errors.New("the movie is a candidate of a poll; delete the session of the poll first")
+)
+
+type crewWatchlistUseCase struct {
+	repoCrew      IRepositoryCrew
+	repoMember    IRepositoryCrewMember
+	repoWatchlist IRepositoryCrewWatchlist
+	repoSession   IRepositoryMovieSession
+	repoMovie     usecase_movies.IRepositoryMovie
+}
+
+func NewCrewWatchlistUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoWatchlist IRepositoryCrewWatchlist, repoSession IRepositoryMovieSession, repoMovie usecase_movies.IRepositoryMovie) IUseCaseCrewWatchlist {
+	return &crewWatchlistUseCase{
+		repoCrew:      repoCrew,
+		repoMember:    repoMember,
+		repoWatchlist: repoWatchlist,
+		repoSession:   repoSession,
+		repoMovie:     repoMovie,
+	}
+}
+
+// syncWatched marks as watched the pending items whose session has ended,
+// and releases the items whose session no longer exists.
+func syncWatched(repoWatchlist IRepositoryCrewWatchlist, repoSession IRepositoryMovieSession, items []*entity_crew.CrewWatchlistItem) error {
+	now := time.Now()
+	for _, item := range items {
+		if item.WatchedAt != nil || item.SessionID == nil {
+			continue
+		}
+
+		session, err := repoSession.FindByIdAndCrew(*item.SessionID, *item.CrewID)
+		switch {
+		case err != nil:
+			item.SessionID = nil
+		case session.EndAt.Before(now):
+			item.WatchedAt = session.EndAt
+		default:
+			continue
+		}
+		if err := repoWatchlist.Update(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *crewWatchlistUseCase) Add(item *entity_crew.CrewWatchlistItem, crewID uuid.UUID, userID int) error {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return err
+	}
+	if item.MovieID == nil {
+		return fmt.Errorf("movie_id is required")
+	}
+
+	movie, err := u.repoMovie.FindById(*item.MovieID)
+	if err != nil {
+		return usecase_movies.ErrMovieNotFound
+	}
+	if _, err := u.repoWatchlist.FindByCrewAndMovie(crewID, *item.MovieID); err == nil {
+		return fmt.Errorf("movie is already in the watchlist")
+	}
+
+	items, err := u.repoWatchlist.FindAllByCrew(crewID)
+	if err != nil {
+		return fmt.Errorf("could not load watchlist")
+	}
+	position := 1
+	for _, existing := range items {
+		if existing.Position >= position {
+			position = existing.Position + 1
+		}
+	}
+
+	item.CrewID = crew.ID
+	item.AddedByID = userID
+	item.Position = position
+	item.SessionID = nil
+	item.WatchedAt = nil
+	if err := u.repoWatchlist.Create(item); err != nil {
+		return fmt.Errorf("could not add movie to the watchlist")
+	}
+	item.Movie = movie
+	return nil
+}
+
+func (u *crewWatchlistUseCase) GetAll(crewID uuid.UUID, userID int, status string) ([]*entity_crew.CrewWatchlistItem, error) {
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID); err != nil {
+		return nil, err
+	}
+	if status == "" {
+		status = WatchlistFilterPending
+	}
+	if status != WatchlistFilterPending && status != WatchlistFilterWatched && status != WatchlistFilterAll {
+		return nil, fmt.Errorf("invalid status: must be '%s', '%s' or '%s'", WatchlistFilterPending, WatchlistFilterWatched, WatchlistFilterAll)
+	}
+
+	items, err := u.repoWatchlist.FindAllByCrew(crewID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load watchlist")
+	}
+	if err := syncWatched(u.repoWatchlist, u.repoSession, items); err != nil {
+		return nil, fmt.Errorf("could not update watched movies")
+	}
+
+	result := []*entity_crew.CrewWatchlistItem{}
+	for _, item := range items {
+		watched := item.WatchedAt != nil
+		if status == WatchlistFilterAll || watched == (status == WatchlistFilterWatched) {
+			result = append(result, item)
+		}
+	}
+	return result, nil
+}
+
+// Move puts a pending item at the given 1-based position among the pending
+// items, renumbering them, and returns them in their new order.
+func (u *crewWatchlistUseCase) Move(id uuid.UUID, crewID uuid.UUID, userID int, position int) ([]*entity_crew.CrewWatchlistItem, error) {
+	pending, err := u.GetAll(crewID, userID, WatchlistFilterPending)
+	if err != nil {
+		return nil, err
+	}
+
+	index := -1
+	for i, item := range pending {
+		if *item.ID == id {
+			index = i
+		}
+	}
+	if index == -1 {
+		if _, err := u.repoWatchlist.FindByIdAndCrew(id, crewID); err == nil {
+			return nil, fmt.Errorf("watched movies can not be reordered")
+		}
+		return nil, ErrWatchlistItemNotFound
+	}
+	if position < 1 || position > len(pending) {
+		return nil, fmt.Errorf("position must be between 1 and %d", len(pending))
+	}
+
+	item := pending[index]
+	pending = append(pending[:index], pending[index+1:]...)
+	pending = append(pending[:position-1], append([]*entity_crew.CrewWatchlistItem{item}, pending[position-1:]...)...)
+	for i, item := range pending {
+		item.Position = i + 1
+	}
+
+	if err := u.repoWatchlist.UpdatePositions(pending); err != nil {
+		return nil, fmt.Errorf("could not reorder watchlist")
+	}
+	return pending, nil
+}
+
+func (u *crewWatchlistUseCase) MarkWatched(id uuid.UUID, crewID uuid.UUID, userID int) (*entity_crew.CrewWatchlistItem, error) {
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID); err != nil {
+		return nil, err
+	}
+
+	item, err := u.repoWatchlist.FindByIdAndCrew(id, crewID)
+	if err != nil {
+		return nil, ErrWatchlistItemNotFound
+	}
+	if item.WatchedAt != nil {
+		return item, nil
+	}
+
+	now := time.Now()
+	item.WatchedAt = &now
+	if err := u.repoWatchlist.Update(item); err != nil {
+		return nil, fmt.Errorf("could not update watchlist item")
+	}
+	return item, nil
+}
+
+// Remove deletes an item; only who added it or a crew owner/admin may.
+func (u *crewWatchlistUseCase) Remove(id uuid.UUID, crewID uuid.UUID, userID int) error {
+	_, member, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return err
+	}
+
+	item, err := u.repoWatchlist.FindByIdAndCrew(id, crewID)
+	if err != nil {
+		return ErrWatchlistItemNotFound
+	}
+	if item.AddedByID != userID && !member.CanManageMembers() {
+		return ErrCrewForbidden
+	}
+	// Ballots and results of closed polls still point at the item
+	inPoll, err := u.repoWatchlist.IsInPoll(id)
+	if err != nil {
+		return fmt.Errorf("could not load polls")
+	}
+	if inPoll {
+		return ErrWatchlistItemInPoll
+	}
+
+	if err := u.repoWatchlist.Delete(id); err != nil {
+		return fmt.Errorf("could not remove movie from the watchlist")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+// instantRunoff counts ranked ballots. Each round a ballot counts for its
+// highest ranked candidate still running; a candidate with more than half
+// of the counted ballots wins, otherwise the one with fewest votes is
+// eliminated. Ties for elimination go to the candidate with fewer votes in
+// the latest earlier round where they differ, then to the one listed last,
+// so candidates must be ordered by preference (watchlist position).
+// The winner is nil when no ballot ranks any candidate.
+func instantRunoff(candidates []uuid.UUID, ballots [][]uuid.UUID) ([]*entity_crew.PollRound, *uuid.UUID) {
+	running := map[uuid.UUID]bool{}
+	for _, candidate := range candidates {
+		running[candidate] = true
+	}
+
+	rounds := []*entity_crew.PollRound{}
+	history := []map[uuid.UUID]int{}
+	for {
+		counts := map[uuid.UUID]int{}
+		round := &entity_crew.PollRound{Round: len(rounds) + 1, Tallies: []*entity_crew.PollTally{}}
+		total := 0
+		for _, ballot := range ballots {
+			counted := false
+			for _, choice := range ballot {
+				if running[choice] {
+					counts[choice]++
+					total++
+					counted = true
+					break
+				}
+			}
+			if !counted {
+				round.Exhausted++
+			}
+		}
+		history = append(history, counts)
+
+		left := []uuid.UUID{}
+		for _, candidate := range candidates {
+			if running[candidate] {
+				left = append(left, candidate)
+				round.Tallies = append(round.Tallies, &entity_crew.PollTally{WatchlistItemID: candidate, Votes: counts[candidate]})
+			}
+		}
+		rounds = append(rounds, round)
+
+		if total == 0 {
+			return rounds, nil
+		}
+		for _, candidate := range left {
+			if counts[candidate]*2 > total || len(left) == 1 {
+				winner := candidate
+				round.Winner = &winner
+				return rounds, &winner
+			}
+		}
+
+		loser := left[len(left)-1]
+		for i := len(left) - 2; i >= 0; i-- {
+			if fewerVotes(left[i], loser, history) {
+				loser = left[i]
+			}
+		}
+		running[loser] = false
+		round.Eliminated = &loser
+	}
+}
+
+// fewerVotes reports whether a had fewer votes than b in the current round,
+// or in the latest earlier round where they differ.
+func fewerVotes(a uuid.UUID, b uuid.UUID, history []map[uuid.UUID]int) bool {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i][a] != history[i][b] {
+			return history[i][a] < history[i][b]
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package usecase_crew
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestInstantRunoff(t *testing.T) {
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	repeat := func(ballot []uuid.UUID, times int) [][]uuid.UUID {
+		ballots := [][]uuid.UUID{}
+		for i := 0; i < times; i++ {
+			ballots = append(ballots, ballot)
+		}
+		return ballots
+	}
+	join := func(groups ...[][]uuid.UUID) [][]uuid.UUID {
+		ballots := [][]uuid.UUID{}
+		for _, group := range groups {
+			ballots = append(ballots, group...)
+		}
+		return ballots
+	}
+
+	tests := []struct {
+		name       string
+		candidates []uuid.UUID
+		ballots    [][]uuid.UUID
+		winner     *uuid.UUID
+		eliminated []uuid.UUID
+		exhausted  int // Exhausted ballots in the last round
+	}{
+		{
+			name:       "no ballots",
+			candidates: []uuid.UUID{a, b},
+			ballots:    [][]uuid.UUID{},
+			eliminated: []uuid.UUID{},
+		},
+		{
+			name:       "ballots ranking no candidate",
+			candidates: []uuid.UUID{a, b},
+			ballots:    [][]uuid.UUID{{}, {uuid.New()}},
+			eliminated: []uuid.UUID{},
+			exhausted:  2,
+		},
+		{
+			name:       "majority in the first round",
+			candidates: []uuid.UUID{a, b, c},
+			ballots:    join(repeat([]uuid.UUID{b}, 3), repeat([]uuid.UUID{a}, 1), repeat([]uuid.UUID{c}, 1)),
+			winner:     &b,
+			eliminated: []uuid.UUID{},
+		},
+		{
+			name:       "votes move to the next choice",
+			candidates: []uuid.UUID{a, b, c},
+			ballots:    join(repeat([]uuid.UUID{a}, 4), repeat([]uuid.UUID{b, c}, 3), repeat([]uuid.UUID{c, b}, 2)),
+			winner:     &b,
+			eliminated: []uuid.UUID{c},
+		},
+		{
+			name:       "tie goes against the candidate listed last",
+			candidates: []uuid.UUID{a, b},
+			ballots:    [][]uuid.UUID{{a}, {b}},
+			winner:     &a,
+			eliminated: []uuid.UUID{b},
+			exhausted:  1,
+		},
+		{
+			name:       "tie broken by the earlier round",
+			candidates: []uuid.UUID{a, b, c, d},
+			ballots: join(
+				repeat([]uuid.UUID{a}, 4),
+				repeat([]uuid.UUID{c}, 3),
+				repeat([]uuid.UUID{b, c}, 2),
+				repeat([]uuid.UUID{d, b}, 1),
+			),
+			winner:     &c,
+			eliminated: []uuid.UUID{d, b},
+			exhausted:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rounds, winner := instantRunoff(tt.candidates, tt.ballots)
+			if !reflect.DeepEqual(winner, tt.winner) {
+				t.Errorf("winner = %v, want %v", winner, tt.winner)
+			}
+
+			eliminated := []uuid.UUID{}
+			for _, round := range rounds {
+				if round.Eliminated != nil {
+					eliminated = append(eliminated, *round.Eliminated)
+				}
+			}
+			if !reflect.DeepEqual(eliminated, tt.eliminated) {
+				t.Errorf("eliminated = %v, want %v", eliminated, tt.eliminated)
+			}
+
+			last := rounds[len(rounds)-1]
+			if !reflect.DeepEqual(last.Winner, tt.winner) {
+				t.Errorf("last round winner = %v, want %v", last.Winner, tt.winner)
+			}
+			if last.Exhausted != tt.exhausted {
+				t.Errorf("last round exhausted = %d, want %d", last.Exhausted, tt.exhausted)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IRepositoryMovieSession interface {
+	Create(session *entity_crew.MovieSession) error
+	FindByIdAndCrew(id uuid.UUID, crewID uuid.UUID) (*entity_crew.MovieSession, error)
+	FindAllByCrewWithFilter(crewID uuid.UUID, startDate, endDate *time.Time) ([]*entity_crew.MovieSession, error)
+	Update(session *entity_crew.MovieSession) error
+	Delete(id uuid.UUID) (bool, error) // False, deleting nothing, when the session has expenses
+}
+
+type IUseCaseMovieSession interface {
+	Create(session *entity_crew.MovieSession, watchlistItemID *uuid.UUID, crewID uuid.UUID, userID int) error
+	GetById(id uuid.UUID, crewID uuid.UUID, userID int) (*entity_crew.MovieSession, error)
+	GetAll(crewID uuid.UUID, userID int, startDate, endDate *time.Time) ([]*entity_crew.MovieSession, error)
+	Update(session *entity_crew.MovieSession, crewID uuid.UUID, userID int) error
+	Delete(id uuid.UUID, crewID uuid.UUID, userID int) error
+}
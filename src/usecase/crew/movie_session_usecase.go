@@ -0,0 +1,235 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	usecase_movies "app/usecase/movies"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionNotFound    = errors.New("movie session not found")
+	ErrSessionHasExpenses = errors.New("movie session has expenses; delete them first")
+)
+
+type movieSessionUseCase struct {
+	repoCrew        IRepositoryCrew
+	repoMember      IRepositoryCrewMember
+	repoSession     IRepositoryMovieSession
+	repoRSVP        IRepositorySessionRSVP
+	transactionRSVP TransactionSessionRSVP
+	repoWatchlist   IRepositoryCrewWatchlist
+	repoMovie       usecase_movies.IRepositoryMovie
+}
+
+func NewMovieSessionUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoSession IRepositoryMovieSession, repoRSVP IRepositorySessionRSVP, transactionRSVP TransactionSessionRSVP, repoWatchlist IRepositoryCrewWatchlist, repoMovie usecase_movies.IRepositoryMovie) IUseCaseMovieSession {
+	return &movieSessionUseCase{repoCrew: repoCrew, repoMember: repoMember, repoSession: repoSession, repoRSVP: repoRSVP, transactionRSVP: transactionRSVP, repoWatchlist: repoWatchlist, repoMovie: repoMovie}
+}
+
+func validateSession(session *entity_crew.MovieSession) error {
+	if strings.TrimSpace(session.Title) == "" {
+		return fmt.Errorf("title is required")
+	}
+	if session.StartAt == nil || session.EndAt == nil {
+		return fmt.Errorf("start_at and end_at are required")
+	}
+	if !session.EndAt.After(*session.StartAt) {
+		return fmt.Errorf("end_at must be after start_at")
+	}
+	if session.Capacity < 0 {
+		return fmt.Errorf("capacity must not be negative")
+	}
+
+	switch session.LocationType {
+	case entity_crew.LocationTypeInPerson:
+		if strings.TrimSpace(session.Location) == "" {
+			return fmt.Errorf("location is required for in person sessions")
+		}
+	case entity_crew.LocationTypeStreaming:
+		link, err := url.Parse(session.StreamingURL)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return fmt.Errorf("a valid streaming_url is required for streaming sessions")
+		}
+	default:
+		return fmt.Errorf("invalid location_type: must be '%s' or '%s'", entity_crew.LocationTypeInPerson, entity_crew.LocationTypeStreaming)
+	}
+	return nil
+}
+
+// Create schedules a session. Picking a watchlist item sets the movie and
+// links the item to the session, so it is marked watched once it ends.
+func (u *movieSessionUseCase) Create(session *entity_crew.MovieSession, watchlistItemID *uuid.UUID, crewID uuid.UUID, userID int) error {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return err
+	}
+
+	var item *entity_crew.CrewWatchlistItem
+	if watchlistItemID != nil {
+		item, err = u.repoWatchlist.FindByIdAndCrew(*watchlistItemID, crewID)
+		if err != nil {
+			return ErrWatchlistItemNotFound
+		}
+		if item.WatchedAt != nil {
+			return fmt.Errorf("the crew already watched this movie")
+		}
+		if item.SessionID != nil {
+			return fmt.Errorf("this movie is already picked for another session")
+		}
+		session.MovieID = item.MovieID
+	}
+
+	movie, err := u.findMovie(session.MovieID)
+	if err != nil {
+		return err
+	}
+	if movie != nil && strings.TrimSpace(session.Title) == "" {
+		session.Title = movie.Title
+	}
+	if err := validateSession(session); err != nil {
+		return err
+	}
+
+	session.CrewID = crew.ID
+	session.OrganizerID = userID
+
+	if err := u.repoSession.Create(session); err != nil {
+		return fmt.Errorf("could not create movie session")
+	}
+	session.Movie = movie
+
+	if item != nil {
+		item.SessionID = session.ID
+		if err := u.repoWatchlist.Update(item); err != nil {
+			return fmt.Errorf("could not link the watchlist item to the session")
+		}
+	}
+	return nil
+}
+
+// findMovie loads the catalog movie of a session, if it has one.
+func (u *movieSessionUseCase) findMovie(movieID *uuid.UUID) (*entity_movies.Movie, error) {
+	if movieID == nil {
+		return nil, nil
+	}
+	movie, err := u.repoMovie.FindById(*movieID)
+	if err != nil {
+		return nil, usecase_movies.ErrMovieNotFound
+	}
+	return movie, nil
+}
+
+func (u *movieSessionUseCase) GetById(id uuid.UUID, crewID uuid.UUID, userID int) (*entity_crew.MovieSession, error) {
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID); err != nil {
+		return nil, err
+	}
+
+	session, err := u.repoSession.FindByIdAndCrew(id, crewID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (u *movieSessionUseCase) GetAll(crewID uuid.UUID, userID int, startDate, endDate *time.Time) ([]*entity_crew.MovieSession, error) {
+	if _, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID); err != nil {
+		return nil, err
+	}
+	return u.repoSession.FindAllByCrewWithFilter(crewID, startDate, endDate)
+}
+
+// findManageable loads a session that userID may edit: its organizer or a
+// crew owner/admin.
+func (u *movieSessionUseCase) findManageable(id uuid.UUID, crewID uuid.UUID, userID int) (*entity_crew.MovieSession, error) {
+	_, member, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := u.repoSession.FindByIdAndCrew(id, crewID)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.OrganizerID != userID && !member.CanManageMembers() {
+		return nil, ErrCrewForbidden
+	}
+	return session, nil
+}
+
+func (u *movieSessionUseCase) Update(session *entity_crew.MovieSession, crewID uuid.UUID, userID int) error {
+	if session.ID == nil {
+		return fmt.Errorf("session id is required")
+	}
+
+	existing, err := u.findManageable(*session.ID, crewID, userID)
+	if err != nil {
+		return err
+	}
+
+	if session.MovieID != nil {
+		movie, err := u.findMovie(session.MovieID)
+		if err != nil {
+			return err
+		}
+		existing.MovieID = movie.ID
+		existing.Movie = movie
+	}
+	existing.Title = session.Title
+	// Like on create, a session of a catalog movie is titled after it by default
+	if existing.MovieID != nil && strings.TrimSpace(existing.Title) == "" {
+		if existing.Movie == nil {
+			movie, err := u.findMovie(existing.MovieID)
+			if err != nil {
+				return err
+			}
+			existing.Movie = movie
+		}
+		existing.Title = existing.Movie.Title
+	}
+	existing.Description = session.Description
+	existing.StartAt = session.StartAt
+	existing.EndAt = session.EndAt
+	existing.LocationType = session.LocationType
+	existing.Location = session.Location
+	existing.StreamingURL = session.StreamingURL
+	existing.Capacity = session.Capacity
+	if err := validateSession(existing); err != nil {
+		return err
+	}
+	existing.UpdatedAt = time.Now()
+
+	if err := u.repoSession.Update(existing); err != nil {
+		return fmt.Errorf("could not update movie session")
+	}
+	// A raised or removed capacity frees spots for the waitlist. Lowering it
+	// keeps everybody who is already going.
+	err = withSessionLock(u.transactionRSVP, *existing.ID, func(repo IRepositorySessionRSVP) error {
+		return promoteWaitlist(repo, existing)
+	})
+	if err != nil {
+		return fmt.Errorf("could not promote waitlist")
+	}
+	*session = *existing
+	return nil
+}
+
+func (u *movieSessionUseCase) Delete(id uuid.UUID, crewID uuid.UUID, userID int) error {
+	if _, err := u.findManageable(id, crewID, userID); err != nil {
+		return err
+	}
+
+	deleted, err := u.repoSession.Delete(id)
+	if err != nil {
+		return fmt.Errorf("could not delete movie session")
+	}
+	if !deleted {
+		return ErrSessionHasExpenses
+	}
+	return nil
+}
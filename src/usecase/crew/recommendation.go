@@ -0,0 +1,299 @@
+package usecase_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultRating       = 3.0 // Mean rating assumed for users who never rated
+	unratedPreference   = 0.5 // Watching a movie without rating it counts as liking it a little
+	similarityShrinkage = 5.0 // Co-rated movies needed for a similarity to count fully
+	predictionShrinkage = 1.0 // Damps predictions backed by little similarity
+	genreShrinkage      = 2.0 // Watched movies needed for a genre affinity to count fully
+	genreWeight         = 0.5
+	maxReasonNames      = 2
+)
+
+// tasteProfile holds the watch history of a user relative to their mean
+// rating, so generous and harsh raters can be compared.
+type tasteProfile struct {
+	mean    float64
+	rated   map[uuid.UUID]float64 // Rating minus mean
+	watched map[uuid.UUID]float64 // Rated movies, and unrated ones at unratedPreference
+}
+
+func buildProfiles(entries []*entity_movies.WatchEntry) map[int]*tasteProfile {
+	sums := map[int]float64{}
+	counts := map[int]int{}
+	for _, entry := range entries {
+		if entry.Rating != nil {
+			sums[entry.UserID] += *entry.Rating
+			counts[entry.UserID]++
+		}
+	}
+
+	profiles := map[int]*tasteProfile{}
+	for _, entry := range entries {
+		profile := profiles[entry.UserID]
+		if profile == nil {
+			profile = &tasteProfile{mean: defaultRating, rated: map[uuid.UUID]float64{}, watched: map[uuid.UUID]float64{}}
+			if counts[entry.UserID] > 0 {
+				profile.mean = sums[entry.UserID] / float64(counts[entry.UserID])
+			}
+			profiles[entry.UserID] = profile
+		}
+		if entry.Rating != nil {
+			profile.rated[*entry.MovieID] = *entry.Rating - profile.mean
+			profile.watched[*entry.MovieID] = *entry.Rating - profile.mean
+		} else {
+			profile.watched[*entry.MovieID] = unratedPreference
+		}
+	}
+	return profiles
+}
+
+// similarity is the cosine of the centered ratings of the movies both users
+// rated, shrunk towards zero when they rated few movies in common.
+func similarity(a *tasteProfile, b *tasteProfile) float64 {
+	dot, normA, normB, common := 0.0, 0.0, 0.0, 0
+	for movieID, ratingA := range a.rated {
+		ratingB, ok := b.rated[movieID]
+		if !ok {
+			continue
+		}
+		dot += ratingA * ratingB
+		normA += ratingA * ratingA
+		normB += ratingB * ratingB
+		common++
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB) * float64(common) / (float64(common) + similarityShrinkage)
+}
+
+// genreAffinity is how much more than usual a user liked each genre.
+func genreAffinity(profile *tasteProfile, movies map[uuid.UUID]*entity_movies.Movie) map[string]float64 {
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for movieID, preference := range profile.watched {
+		movie := movies[movieID]
+		if movie == nil {
+			continue
+		}
+		for _, genre := range movie.Genres {
+			sums[genre] += preference
+			counts[genre]++
+		}
+	}
+
+	affinity := map[string]float64{}
+	for genre, sum := range sums {
+		affinity[genre] = sum / (float64(counts[genre]) + genreShrinkage)
+	}
+	return affinity
+}
+
+// crewGenreAffinity averages the genre affinity of the members, members who
+// never watched a genre counting as indifferent to it.
+func crewGenreAffinity(members []*entity_crew.CrewMember, profiles map[int]*tasteProfile, movies map[uuid.UUID]*entity_movies.Movie) map[string]float64 {
+	affinity := map[string]float64{}
+	for _, member := range members {
+		profile := profiles[member.UserID]
+		if profile == nil {
+			continue
+		}
+		for genre, value := range genreAffinity(profile, movies) {
+			affinity[genre] += value / float64(len(members))
+		}
+	}
+	return affinity
+}
+
+// favoriteGenres returns the genres the crew likes more than usual, most
+// liked first.
+func favoriteGenres(affinity map[string]float64, limit int) []string {
+	genres := []string{}
+	for genre, value := range affinity {
+		if value > 0 {
+			genres = append(genres, genre)
+		}
+	}
+	sort.Slice(genres, func(a, b int) bool {
+		if affinity[genres[a]] != affinity[genres[b]] {
+			return affinity[genres[a]] > affinity[genres[b]]
+		}
+		return genres[a] < genres[b]
+	})
+	if len(genres) > limit {
+		genres = genres[:limit]
+	}
+	return genres
+}
+
+// recommendMovies predicts how much the crew would like each candidate with
+// user-based collaborative filtering: a member's opinion of a movie is their
+// own rating when they watched it, or else the ratings of similar users
+// weighted by similarity. The average opinion of the members is blended with
+// how well the genres of the movie match the crew's taste. Entries must hold
+// the watch history of the members and of the similar users, with movies.
+func recommendMovies(members []*entity_crew.CrewMember, entries []*entity_movies.WatchEntry, candidates []*entity_movies.Movie, excluded map[uuid.UUID]bool, limit int) []*entity_crew.MovieRecommendation {
+	isMember := map[int]bool{}
+	for _, member := range members {
+		isMember[member.UserID] = true
+	}
+	movies := map[uuid.UUID]*entity_movies.Movie{}
+	for _, movie := range candidates {
+		movies[*movie.ID] = movie
+	}
+	for _, entry := range entries {
+		if entry.Movie != nil {
+			movies[*entry.MovieID] = entry.Movie
+		}
+	}
+
+	profiles := buildProfiles(entries)
+	neighbors := map[int]map[int]float64{} // Similar users of each member
+	for _, member := range members {
+		profile := profiles[member.UserID]
+		if profile == nil {
+			continue
+		}
+		neighbors[member.UserID] = map[int]float64{}
+		for userID, other := range profiles {
+			if isMember[userID] {
+				continue
+			}
+			if sim := similarity(profile, other); sim > 0 {
+				neighbors[member.UserID][userID] = sim
+			}
+		}
+	}
+
+	base, rated := 0.0, 0
+	for _, member := range members {
+		if profile := profiles[member.UserID]; profile != nil && len(profile.rated) > 0 {
+			base += profile.mean
+			rated++
+		}
+	}
+	if rated > 0 {
+		base /= float64(rated)
+	} else {
+		base = defaultRating
+	}
+	affinity := crewGenreAffinity(members, profiles, movies)
+
+	recommendations := []*entity_crew.MovieRecommendation{}
+	for movieID, movie := range movies {
+		if excluded[movieID] {
+			continue
+		}
+
+		opinions, watchedBy, likedBy := 0.0, 0, []*entity_accounts.User{}
+		predicted := map[int]float64{}
+		for _, member := range members {
+			profile := profiles[member.UserID]
+			if profile == nil {
+				continue
+			}
+			if preference, ok := profile.watched[movieID]; ok {
+				opinions += preference
+				watchedBy++
+				if preference > 0 {
+					likedBy = append(likedBy, member.User)
+				}
+				continue
+			}
+
+			weighted, weights := 0.0, 0.0
+			for userID, sim := range neighbors[member.UserID] {
+				if rating, ok := profiles[userID].rated[movieID]; ok {
+					weighted += sim * rating
+					weights += sim
+				}
+			}
+			if weights > 0 {
+				predicted[member.UserID] = weighted / (weights + predictionShrinkage)
+				opinions += predicted[member.UserID]
+			}
+		}
+
+		opinion := 0.0
+		if count := watchedBy + len(predicted); count > 0 {
+			opinion = opinions / float64(count)
+		}
+		genreMatch := 0.0
+		for _, genre := range movie.Genres {
+			genreMatch += affinity[genre] / float64(len(movie.Genres))
+		}
+		if opinion+genreWeight*genreMatch <= 0 {
+			continue
+		}
+
+		score := math.Max(entity_movies.MinRating, math.Min(entity_movies.MaxRating, base+opinion+genreWeight*genreMatch))
+		recommendations = append(recommendations, &entity_crew.MovieRecommendation{
+			Movie:  movie,
+			Score:  math.Round(score*100) / 100,
+			Reason: recommendationReason(members, movie, likedBy, predicted, opinion, genreWeight*genreMatch, affinity),
+		})
+	}
+
+	sort.Slice(recommendations, func(a, b int) bool {
+		if recommendations[a].Score != recommendations[b].Score {
+			return recommendations[a].Score > recommendations[b].Score
+		}
+		return recommendations[a].Movie.Title < recommendations[b].Movie.Title
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations
+}
+
+// recommendationReason explains the strongest signal behind a recommendation.
+func recommendationReason(members []*entity_crew.CrewMember, movie *entity_movies.Movie, likedBy []*entity_accounts.User, predicted map[int]float64, opinion float64, genreMatch float64, affinity map[string]float64) string {
+	if opinion >= genreMatch {
+		if len(likedBy) > 0 {
+			if len(likedBy) > maxReasonNames {
+				likedBy = likedBy[:maxReasonNames]
+			}
+			return "Liked by " + memberNames(likedBy)
+		}
+
+		similar := []*entity_crew.CrewMember{}
+		for _, member := range members {
+			if predicted[member.UserID] > 0 {
+				similar = append(similar, member)
+			}
+		}
+		sort.SliceStable(similar, func(a, b int) bool { return predicted[similar[a].UserID] > predicted[similar[b].UserID] })
+		users := []*entity_accounts.User{}
+		for _, member := range similar {
+			if len(users) < maxReasonNames {
+				users = append(users, member.User)
+			}
+		}
+		if len(users) > 0 {
+			return "Rated highly by people with similar taste to " + memberNames(users)
+		}
+	}
+
+	movieAffinity := map[string]float64{}
+	for _, genre := range movie.Genres {
+		movieAffinity[genre] = affinity[genre]
+	}
+	genres := favoriteGenres(movieAffinity, maxReasonNames)
+	if len(genres) == 0 {
+		return "Rated highly by people with similar taste"
+	}
+	return fmt.Sprintf("Matches the crew's taste for %s", strings.Join(genres, " and "))
+}
@@ -0,0 +1,31 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+type IRepositorySessionExpense interface {
+	Create(expense *entity_crew.SessionExpense) error
+	FindByIdAndSession(id uuid.UUID, sessionID uuid.UUID) (*entity_crew.SessionExpense, error)
+	FindAllBySession(sessionID uuid.UUID) ([]*entity_crew.SessionExpense, error)
+	FindAllByCrew(crewID uuid.UUID) ([]*entity_crew.SessionExpense, error)
+	FindSharesBySessionAndDebtor(sessionID uuid.UUID, debtorID int) ([]*entity_crew.ExpenseShare, error)
+	Delete(id uuid.UUID) error
+}
+
+// ExpenseParticipant is one participant of a split: Shares is used by the
+// shares mode and AmountCents by the exact mode.
+type ExpenseParticipant struct {
+	UserID      int
+	Shares      int
+	AmountCents int64
+}
+
+type IUseCaseSessionExpense interface {
+	Create(expense *entity_crew.SessionExpense, participants []ExpenseParticipant, crewID uuid.UUID, sessionID uuid.UUID, userID int) error
+	GetAll(crewID uuid.UUID, sessionID uuid.UUID, userID int) ([]*entity_crew.SessionExpense, error)
+	Delete(id uuid.UUID, crewID uuid.UUID, sessionID uuid.UUID, userID int) error
+	GetDebts(crewID uuid.UUID, sessionID uuid.UUID, userID int) ([]*entity_crew.ExpenseDebt, error)
+}
@@ -0,0 +1,239 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	usecase_accounts "app/usecase/accounts"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrExpenseNotFound = errors.New("expense not found")
+
+type sessionExpenseUseCase struct {
+	repoCrew    IRepositoryCrew
+	repoMember  IRepositoryCrewMember
+	repoSession IRepositoryMovieSession
+	repoRSVP    IRepositorySessionRSVP
+	repoExpense IRepositorySessionExpense
+	repoPix     usecase_accounts.IRepositoryUserPix
+}
+
+func NewSessionExpenseUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoSession IRepositoryMovieSession, repoRSVP IRepositorySessionRSVP, repoExpense IRepositorySessionExpense, repoPix usecase_accounts.IRepositoryUserPix) IUseCaseSessionExpense {
+	return &sessionExpenseUseCase{
+		repoCrew:    repoCrew,
+		repoMember:  repoMember,
+		repoSession: repoSession,
+		repoRSVP:    repoRSVP,
+		repoExpense: repoExpense,
+		repoPix:     repoPix,
+	}
+}
+
+// splitExpense computes each participant's amount. Cents that cannot be
+// divided evenly go to the first participants, one cent each.
+func splitExpense(total int64, mode string, participants []ExpenseParticipant) ([]*entity_crew.ExpenseShare, error) {
+	if len(participants) == 0 {
+		return nil, fmt.Errorf("at least one participant is required")
+	}
+
+	weights := make([]int64, len(participants))
+	switch mode {
+	case entity_crew.SplitModeEqual:
+		for i := range participants {
+			weights[i] = 1
+		}
+	case entity_crew.SplitModeShares:
+		for i, p := range participants {
+			if p.Shares <= 0 {
+				return nil, fmt.Errorf("shares must be positive for every participant")
+			}
+			weights[i] = int64(p.Shares)
+		}
+	case entity_crew.SplitModeExact:
+		sum := int64(0)
+		shares := make([]*entity_crew.ExpenseShare, len(participants))
+		for i, p := range participants {
+			if p.AmountCents < 0 {
+				return nil, fmt.Errorf("amounts must not be negative")
+			}
+			sum += p.AmountCents
+			shares[i] = &entity_crew.ExpenseShare{DebtorID: p.UserID, AmountCents: p.AmountCents}
+		}
+		if sum != total {
+			return nil, fmt.Errorf("exact amounts add up to %d cents, expected %d", sum, total)
+		}
+		return shares, nil
+	default:
+		return nil, fmt.Errorf("invalid split_mode: must be '%s', '%s' or '%s'", entity_crew.SplitModeEqual, entity_crew.SplitModeShares, entity_crew.SplitModeExact)
+	}
+
+	totalWeight := int64(0)
+	for _, w := range weights {
+		totalWeight += w
+	}
+
+	shares := make([]*entity_crew.ExpenseShare, len(participants))
+	assigned := int64(0)
+	for i, p := range participants {
+		amount := total * weights[i] / totalWeight
+		assigned += amount
+		shares[i] = &entity_crew.ExpenseShare{DebtorID: p.UserID, Shares: int(weights[i]), AmountCents: amount}
+	}
+	for i := 0; assigned < total; i = (i + 1) % len(shares) {
+		shares[i].AmountCents++
+		assigned++
+	}
+	return shares, nil
+}
+
+func (u *sessionExpenseUseCase) findSession(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.Crew, *entity_crew.CrewMember, *entity_crew.MovieSession, error) {
+	crew, member, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	session, err := u.repoSession.FindByIdAndCrew(sessionID, crewID)
+	if err != nil {
+		return nil, nil, nil, ErrSessionNotFound
+	}
+	return crew, member, session, nil
+}
+
+func (u *sessionExpenseUseCase) Create(expense *entity_crew.SessionExpense, participants []ExpenseParticipant, crewID uuid.UUID, sessionID uuid.UUID, userID int) error {
+	crew, _, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(expense.Description) == "" {
+		return fmt.Errorf("description is required")
+	}
+	if expense.AmountCents <= 0 {
+		return fmt.Errorf("amount_cents must be positive")
+	}
+	if expense.SplitMode == "" {
+		expense.SplitMode = entity_crew.SplitModeEqual
+	}
+
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return fmt.Errorf("could not load crew members")
+	}
+	isMember := map[int]bool{}
+	for _, member := range members {
+		isMember[member.UserID] = true
+	}
+
+	// Without explicit participants an equal split is shared by everybody going.
+	if len(participants) == 0 {
+		if expense.SplitMode != entity_crew.SplitModeEqual {
+			return fmt.Errorf("participants are required for the '%s' split mode", expense.SplitMode)
+		}
+		rsvps, err := u.repoRSVP.FindAllBySession(sessionID)
+		if err != nil {
+			return fmt.Errorf("could not load attendees")
+		}
+		for _, rsvp := range rsvps {
+			if rsvp.Status == entity_crew.RSVPStatusGoing {
+				participants = append(participants, ExpenseParticipant{UserID: rsvp.UserID})
+			}
+		}
+		if len(participants) == 0 {
+			return fmt.Errorf("nobody is going to the session; list the participants explicitly")
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, p := range participants {
+		if !isMember[p.UserID] {
+			return fmt.Errorf("user %d is not a member of the crew", p.UserID)
+		}
+		if seen[p.UserID] {
+			return fmt.Errorf("user %d is listed more than once", p.UserID)
+		}
+		seen[p.UserID] = true
+	}
+
+	shares, err := splitExpense(expense.AmountCents, expense.SplitMode, participants)
+	if err != nil {
+		return err
+	}
+
+	expense.CrewID = crew.ID
+	expense.SessionID = session.ID
+	expense.PayerID = userID
+	expense.Shares = shares
+
+	if err := u.repoExpense.Create(expense); err != nil {
+		return fmt.Errorf("could not create expense")
+	}
+	return nil
+}
+
+func (u *sessionExpenseUseCase) GetAll(crewID uuid.UUID, sessionID uuid.UUID, userID int) ([]*entity_crew.SessionExpense, error) {
+	if _, _, _, err := u.findSession(crewID, sessionID, userID); err != nil {
+		return nil, err
+	}
+	return u.repoExpense.FindAllBySession(sessionID)
+}
+
+func (u *sessionExpenseUseCase) Delete(id uuid.UUID, crewID uuid.UUID, sessionID uuid.UUID, userID int) error {
+	_, member, _, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	expense, err := u.repoExpense.FindByIdAndSession(id, sessionID)
+	if err != nil {
+		return ErrExpenseNotFound
+	}
+	if expense.PayerID != userID && !member.CanManageMembers() {
+		return ErrCrewForbidden
+	}
+
+	if err := u.repoExpense.Delete(id); err != nil {
+		return fmt.Errorf("could not delete expense")
+	}
+	return nil
+}
+
+// payeePixKey returns the primary Pix key of a creditor, if any. Keys
+// registered before primary keys existed fall back to the oldest one.
+func payeePixKey(repoPix usecase_accounts.IRepositoryUserPix, userID int) *string {
+	if key, err := repoPix.FindPrimaryByOwner(userID); err == nil {
+		return &key.PixKey
+	}
+	keys, err := repoPix.GetAllByOwner(userID)
+	if err != nil || len(keys) == 0 {
+		return nil
+	}
+	return &keys[0].PixKey
+}
+
+func (u *sessionExpenseUseCase) GetDebts(crewID uuid.UUID, sessionID uuid.UUID, userID int) ([]*entity_crew.ExpenseDebt, error) {
+	if _, _, _, err := u.findSession(crewID, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	shares, err := u.repoExpense.FindSharesBySessionAndDebtor(sessionID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load debts")
+	}
+
+	debts := []*entity_crew.ExpenseDebt{}
+	for _, share := range shares {
+		// Nobody owes themselves their own share.
+		if share.Expense == nil || share.Expense.PayerID == userID || share.AmountCents == 0 {
+			continue
+		}
+		debts = append(debts, &entity_crew.ExpenseDebt{
+			ExpenseID:   share.ExpenseID,
+			Description: share.Expense.Description,
+			Creditor:    share.Expense.Payer,
+			AmountCents: share.AmountCents,
+			PixKey:      payeePixKey(u.repoPix, share.Expense.PayerID),
+		})
+	}
+	return debts, nil
+}
@@ -0,0 +1,121 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"reflect"
+	"testing"
+)
+
+func TestSplitExpense(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int64
+		mode         string
+		participants []ExpenseParticipant
+		want         []int64
+		wantErr      bool
+	}{
+		{
+			name:         "equal split without remainder",
+			total:        3000,
+			mode:         entity_crew.SplitModeEqual,
+			participants: []ExpenseParticipant{{UserID: 1}, {UserID: 2}, {UserID: 3}},
+			want:         []int64{1000, 1000, 1000},
+		},
+		{
+			name:         "equal split gives the remainder cents to the first participants",
+			total:        1001,
+			mode:         entity_crew.SplitModeEqual,
+			participants: []ExpenseParticipant{{UserID: 1}, {UserID: 2}, {UserID: 3}},
+			want:         []int64{334, 334, 333},
+		},
+		{
+			name:         "one cent among many",
+			total:        1,
+			mode:         entity_crew.SplitModeEqual,
+			participants: []ExpenseParticipant{{UserID: 1}, {UserID: 2}, {UserID: 3}},
+			want:         []int64{1, 0, 0},
+		},
+		{
+			name:         "shares split by weight",
+			total:        1000,
+			mode:         entity_crew.SplitModeShares,
+			participants: []ExpenseParticipant{{UserID: 1, Shares: 2}, {UserID: 2, Shares: 1}, {UserID: 3, Shares: 1}},
+			want:         []int64{500, 250, 250},
+		},
+		{
+			name:         "shares split with remainder",
+			total:        1000,
+			mode:         entity_crew.SplitModeShares,
+			participants: []ExpenseParticipant{{UserID: 1, Shares: 1}, {UserID: 2, Shares: 1}, {UserID: 3, Shares: 1}},
+			want:         []int64{334, 333, 333},
+		},
+		{
+			name:         "shares must be positive",
+			total:        1000,
+			mode:         entity_crew.SplitModeShares,
+			participants: []ExpenseParticipant{{UserID: 1, Shares: 1}, {UserID: 2, Shares: 0}},
+			wantErr:      true,
+		},
+		{
+			name:         "exact amounts",
+			total:        1000,
+			mode:         entity_crew.SplitModeExact,
+			participants: []ExpenseParticipant{{UserID: 1, AmountCents: 700}, {UserID: 2, AmountCents: 300}},
+			want:         []int64{700, 300},
+		},
+		{
+			name:         "exact amounts must add up to the total",
+			total:        1000,
+			mode:         entity_crew.SplitModeExact,
+			participants: []ExpenseParticipant{{UserID: 1, AmountCents: 700}, {UserID: 2, AmountCents: 200}},
+			wantErr:      true,
+		},
+		{
+			name:         "exact amounts must not be negative",
+			total:        1000,
+			mode:         entity_crew.SplitModeExact,
+			participants: []ExpenseParticipant{{UserID: 1, AmountCents: 1100}, {UserID: 2, AmountCents: -100}},
+			wantErr:      true,
+		},
+		{
+			name:    "participants are required",
+			total:   1000,
+			mode:    entity_crew.SplitModeEqual,
+			wantErr: true,
+		},
+		{
+			name:         "unknown mode",
+			total:        1000,
+			mode:         "percent",
+			participants: []ExpenseParticipant{{UserID: 1}},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := splitExpense(tt.total, tt.mode, tt.participants)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", shares)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := make([]int64, len(shares))
+			for i, share := range shares {
+				got[i] = share.AmountCents
+				if share.DebtorID != tt.participants[i].UserID {
+					t.Errorf("share %d is for user %d, want %d", i, share.DebtorID, tt.participants[i].UserID)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("amounts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
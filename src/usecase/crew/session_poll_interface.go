@@ -0,0 +1,24 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IRepositorySessionPoll interface {
+	Create(poll *entity_crew.SessionPoll) error
+	FindBySession(sessionID uuid.UUID) (*entity_crew.SessionPoll, error)
+	Update(poll *entity_crew.SessionPoll) error
+	FindBallot(pollID uuid.UUID, voterID int) (*entity_crew.PollBallot, error)
+	FindAllBallots(pollID uuid.UUID) ([]*entity_crew.PollBallot, error)
+	SaveBallot(ballot *entity_crew.PollBallot) error
+}
+
+type IUseCaseSessionPoll interface {
+	Open(crewID uuid.UUID, sessionID uuid.UUID, userID int, itemIDs []uuid.UUID, deadline time.Time) (*entity_crew.SessionPollResult, error)
+	GetResult(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.SessionPollResult, error)
+	Vote(crewID uuid.UUID, sessionID uuid.UUID, userID int, ranking []uuid.UUID) (*entity_crew.SessionPollResult, error)
+	Close(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.SessionPollResult, error)
+}
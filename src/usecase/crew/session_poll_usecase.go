@@ -0,0 +1,323 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrPollNotFound = errors.New("poll not found")
+
+const MinPollCandidates = 2
+
+type sessionPollUseCase struct {
+	repoCrew      IRepositoryCrew
+	repoMember    IRepositoryCrewMember
+	repoSession   IRepositoryMovieSession
+	repoWatchlist IRepositoryCrewWatchlist
+	repoPoll      IRepositorySessionPoll
+}
+
+func NewSessionPollUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoSession IRepositoryMovieSession, repoWatchlist IRepositoryCrewWatchlist, repoPoll IRepositorySessionPoll) IUseCaseSessionPoll {
+	return &sessionPollUseCase{
+		repoCrew:      repoCrew,
+		repoMember:    repoMember,
+		repoSession:   repoSession,
+		repoWatchlist: repoWatchlist,
+		repoPoll:      repoPoll,
+	}
+}
+
+func (u *sessionPollUseCase) findSession(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.Crew, *entity_crew.CrewMember, *entity_crew.MovieSession, error) {
+	crew, member, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	session, err := u.repoSession.FindByIdAndCrew(sessionID, crewID)
+	if err != nil {
+		return nil, nil, nil, ErrSessionNotFound
+	}
+	return crew, member, session, nil
+}
+
+// Open starts a poll among pending watchlist movies. Only the organizer of
+// the session or a crew owner/admin may open it, once per session.
+func (u *sessionPollUseCase) Open(crewID uuid.UUID, sessionID uuid.UUID, userID int, itemIDs []uuid.UUID, deadline time.Time) (*entity_crew.SessionPollResult, error) {
+	crew, member, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.OrganizerID != userID && !member.CanManageMembers() {
+		return nil, ErrCrewForbidden
+	}
+	if session.MovieID != nil {
+		return nil, fmt.Errorf("the session already has a movie")
+	}
+	if _, err := u.repoPoll.FindBySession(sessionID); err == nil {
+		return nil, fmt.Errorf("the session already has a poll")
+	}
+	if !deadline.After(time.Now()) {
+		return nil, fmt.Errorf("deadline must be in the future")
+	}
+	if session.StartAt != nil && deadline.After(*session.StartAt) {
+		return nil, fmt.Errorf("deadline must not be after the session starts")
+	}
+
+	seen := map[uuid.UUID]bool{}
+	candidates := []*entity_crew.PollCandidate{}
+	for _, itemID := range itemIDs {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		item, err := u.repoWatchlist.FindByIdAndCrew(itemID, crewID)
+		if err != nil {
+			return nil, ErrWatchlistItemNotFound
+		}
+		if item.WatchedAt != nil || item.SessionID != nil {
+			return nil, fmt.Errorf("watchlist item %s was already watched or picked for a session", itemID)
+		}
+		candidates = append(candidates, &entity_crew.PollCandidate{WatchlistItemID: item.ID})
+	}
+	if len(candidates) < MinPollCandidates {
+		return nil, fmt.Errorf("a poll needs at least %d different movies", MinPollCandidates)
+	}
+
+	poll := entity_crew.SessionPoll{
+		CrewID:      crew.ID,
+		SessionID:   session.ID,
+		CreatedByID: userID,
+		Candidates:  candidates,
+		Deadline:    &deadline,
+	}
+	if err := u.repoPoll.Create(&poll); err != nil {
+		return nil, fmt.Errorf("could not create poll")
+	}
+	return u.GetResult(crewID, sessionID, userID)
+}
+
+// tally runs the instant-runoff count over the ballots of a poll, with
+// candidates ordered by their watchlist position.
+func (u *sessionPollUseCase) tally(poll *entity_crew.SessionPoll, ballots []*entity_crew.PollBallot) ([]*entity_crew.PollRound, *uuid.UUID) {
+	candidates := []*entity_crew.PollCandidate{}
+	for _, candidate := range poll.Candidates {
+		if candidate.WatchlistItem != nil {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].WatchlistItem.Position < candidates[b].WatchlistItem.Position
+	})
+
+	ids := []uuid.UUID{}
+	for _, candidate := range candidates {
+		ids = append(ids, *candidate.WatchlistItemID)
+	}
+	rankings := [][]uuid.UUID{}
+	for _, ballot := range ballots {
+		rankings = append(rankings, ballot.Ranking)
+	}
+	return instantRunoff(ids, rankings)
+}
+
+// close stores the winner of the poll and makes it the movie of the
+// session, linking the watchlist item as if it had been picked.
+func (u *sessionPollUseCase) close(poll *entity_crew.SessionPoll, session *entity_crew.MovieSession, ballots []*entity_crew.PollBallot) error {
+	_, winner := u.tally(poll, ballots)
+	now := time.Now()
+	poll.ClosedAt = &now
+	poll.WinnerItemID = winner
+	if err := u.repoPoll.Update(poll); err != nil {
+		return fmt.Errorf("could not close poll")
+	}
+	if winner == nil {
+		return nil
+	}
+
+	item, err := u.repoWatchlist.FindByIdAndCrew(*winner, *poll.CrewID)
+	if err != nil || item.WatchedAt != nil || item.SessionID != nil || session.MovieID != nil {
+		return nil
+	}
+	session.MovieID = item.MovieID
+	session.Movie = item.Movie
+	if err := u.repoSession.Update(session); err != nil {
+		return fmt.Errorf("could not set the movie of the session")
+	}
+	item.SessionID = session.ID
+	if err := u.repoWatchlist.Update(item); err != nil {
+		return fmt.Errorf("could not link the watchlist item to the session")
+	}
+	return nil
+}
+
+// load returns the poll of a session and the ballots of current members;
+// ballots of who left the crew no longer count.
+func (u *sessionPollUseCase) load(crew *entity_crew.Crew, session *entity_crew.MovieSession) (*entity_crew.SessionPoll, []*entity_crew.PollBallot, int, error) {
+	poll, err := u.repoPoll.FindBySession(*session.ID)
+	if err != nil {
+		return nil, nil, 0, ErrPollNotFound
+	}
+	allBallots, err := u.repoPoll.FindAllBallots(*poll.ID)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not load ballots")
+	}
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("could not load crew members")
+	}
+
+	isMember := map[int]bool{}
+	for _, member := range members {
+		isMember[member.UserID] = true
+	}
+	ballots := []*entity_crew.PollBallot{}
+	for _, ballot := range allBallots {
+		if isMember[ballot.VoterID] {
+			ballots = append(ballots, ballot)
+		}
+	}
+	return poll, ballots, len(members), nil
+}
+
+// isOver reports whether an open poll should be closed: its deadline
+// passed or every member voted.
+func isOver(poll *entity_crew.SessionPoll, ballots []*entity_crew.PollBallot, eligible int) bool {
+	return poll.ClosedAt == nil && (!poll.Deadline.After(time.Now()) || len(ballots) >= eligible)
+}
+
+// result builds the view of a poll. A poll past its deadline is shown
+// closed with its computed winner even before a write stores it.
+func (u *sessionPollUseCase) result(poll *entity_crew.SessionPoll, ballots []*entity_crew.PollBallot, eligible int, userID int) *entity_crew.SessionPollResult {
+	status := poll.Status()
+	if poll.ClosedAt == nil && !poll.Deadline.After(time.Now()) {
+		status = entity_crew.PollStatusClosed
+	}
+	result := &entity_crew.SessionPollResult{
+		Poll:           poll,
+		Status:         status,
+		EligibleVoters: eligible,
+		Votes:          len(ballots),
+		MyRanking:      []uuid.UUID{},
+		Rounds:         []*entity_crew.PollRound{},
+	}
+	for _, ballot := range ballots {
+		if ballot.VoterID == userID {
+			result.MyRanking = ballot.Ranking
+		}
+	}
+	if status != entity_crew.PollStatusClosed {
+		return result
+	}
+
+	rounds, winner := u.tally(poll, ballots)
+	result.Rounds = rounds
+	if poll.ClosedAt != nil {
+		winner = poll.WinnerItemID
+	}
+	for _, candidate := range poll.Candidates {
+		if winner != nil && *candidate.WatchlistItemID == *winner {
+			result.Winner = candidate.WatchlistItem
+		}
+	}
+	return result
+}
+
+func (u *sessionPollUseCase) GetResult(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.SessionPollResult, error) {
+	crew, _, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	poll, ballots, eligible, err := u.load(crew, session)
+	if err != nil {
+		return nil, err
+	}
+	return u.result(poll, ballots, eligible, userID), nil
+}
+
+// Vote stores or replaces the ballot of the user while the poll is open.
+// The ranking lists watchlist item IDs of candidates, favorite first.
+func (u *sessionPollUseCase) Vote(crewID uuid.UUID, sessionID uuid.UUID, userID int, ranking []uuid.UUID) (*entity_crew.SessionPollResult, error) {
+	crew, _, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	poll, ballots, eligible, err := u.load(crew, session)
+	if err != nil {
+		return nil, err
+	}
+	// A poll past its deadline is stored as closed by the first vote after it
+	if poll.ClosedAt == nil && !poll.Deadline.After(time.Now()) {
+		if err := u.close(poll, session, ballots); err != nil {
+			return nil, err
+		}
+	}
+	if poll.ClosedAt != nil {
+		return nil, fmt.Errorf("the poll is closed")
+	}
+
+	candidates := map[uuid.UUID]bool{}
+	for _, candidate := range poll.Candidates {
+		candidates[*candidate.WatchlistItemID] = true
+	}
+	if len(ranking) == 0 {
+		return nil, fmt.Errorf("ranking must have at least one movie")
+	}
+	seen := map[uuid.UUID]bool{}
+	for _, itemID := range ranking {
+		if !candidates[itemID] {
+			return nil, fmt.Errorf("%s is not a candidate of this poll", itemID)
+		}
+		if seen[itemID] {
+			return nil, fmt.Errorf("%s is ranked more than once", itemID)
+		}
+		seen[itemID] = true
+	}
+
+	ballot, err := u.repoPoll.FindBallot(*poll.ID, userID)
+	if err != nil {
+		ballot = &entity_crew.PollBallot{PollID: poll.ID, VoterID: userID}
+	}
+	ballot.Ranking = ranking
+	if err := u.repoPoll.SaveBallot(ballot); err != nil {
+		return nil, fmt.Errorf("could not save ballot")
+	}
+
+	// The last missing ballot closes the poll
+	poll, ballots, eligible, err = u.load(crew, session)
+	if err != nil {
+		return nil, err
+	}
+	if isOver(poll, ballots, eligible) {
+		if err := u.close(poll, session, ballots); err != nil {
+			return nil, err
+		}
+	}
+	return u.result(poll, ballots, eligible, userID), nil
+}
+
+func (u *sessionPollUseCase) Close(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.SessionPollResult, error) {
+	crew, member, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	poll, ballots, eligible, err := u.load(crew, session)
+	if err != nil {
+		return nil, err
+	}
+	// Once the poll is over anyone may store its result
+	if poll.ClosedAt == nil && !isOver(poll, ballots, eligible) && poll.CreatedByID != userID && session.OrganizerID != userID && !member.CanManageMembers() {
+		return nil, ErrCrewForbidden
+	}
+
+	if poll.ClosedAt == nil {
+		if err := u.close(poll, session, ballots); err != nil {
+			return nil, err
+		}
+	}
+	return u.result(poll, ballots, eligible, userID), nil
+}
@@ -0,0 +1,28 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+
+	"github.com/google/uuid"
+)
+
+type IRepositorySessionRSVP interface {
+	Create(rsvp *entity_crew.SessionRSVP) error
+	FindBySessionAndUser(sessionID uuid.UUID, userID int) (*entity_crew.SessionRSVP, error)
+	FindAllBySession(sessionID uuid.UUID) ([]*entity_crew.SessionRSVP, error)
+	FindWaitlistBySession(sessionID uuid.UUID) ([]*entity_crew.SessionRSVP, error)
+	CountBySessionAndStatus(sessionID uuid.UUID, status string) (int, error)
+	Update(rsvp *entity_crew.SessionRSVP) error
+	Delete(id uuid.UUID) error
+	LockSession(sessionID uuid.UUID) error
+}
+
+// TransactionSessionRSVP runs fn with a repository bound to a transaction,
+// which is committed when fn returns nil and rolled back otherwise.
+type TransactionSessionRSVP func(fn func(repo IRepositorySessionRSVP) error) error
+
+type IUseCaseSessionRSVP interface {
+	Respond(crewID uuid.UUID, sessionID uuid.UUID, userID int, status string) (*entity_crew.SessionRSVP, error)
+	Withdraw(crewID uuid.UUID, sessionID uuid.UUID, userID int) error
+	GetSummary(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.SessionRSVPSummary, error)
+}
@@ -0,0 +1,223 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type sessionRSVPUseCase struct {
+	repoCrew        IRepositoryCrew
+	repoMember      IRepositoryCrewMember
+	repoSession     IRepositoryMovieSession
+	repoRSVP        IRepositorySessionRSVP
+	transactionRSVP TransactionSessionRSVP
+}
+
+func NewSessionRSVPUseCase(repoCrew IRepositoryCrew, repoMember IRepositoryCrewMember, repoSession IRepositoryMovieSession, repoRSVP IRepositorySessionRSVP, transactionRSVP TransactionSessionRSVP) IUseCaseSessionRSVP {
+	return &sessionRSVPUseCase{repoCrew: repoCrew, repoMember: repoMember, repoSession: repoSession, repoRSVP: repoRSVP, transactionRSVP: transactionRSVP}
+}
+
+// withSessionLock runs fn in a transaction that holds the lock of the
+// session, so the going count and the responses saved from it cannot race
+// with other responses and overbook the session.
+func withSessionLock(transaction TransactionSessionRSVP, sessionID uuid.UUID, fn func(repo IRepositorySessionRSVP) error) error {
+	return transaction(func(repo IRepositorySessionRSVP) error {
+		if err := repo.LockSession(sessionID); err != nil {
+			return fmt.Errorf("could not lock session")
+		}
+		return fn(repo)
+	})
+}
+
+// promoteWaitlist moves waitlisted members to going, oldest first, while the
+// session has free spots.
+func promoteWaitlist(repoRSVP IRepositorySessionRSVP, session *entity_crew.MovieSession) error {
+	waitlist, err := repoRSVP.FindWaitlistBySession(*session.ID)
+	if err != nil {
+		return err
+	}
+	if len(waitlist) == 0 {
+		return nil
+	}
+
+	free := len(waitlist)
+	if session.Capacity > 0 {
+		going, err := repoRSVP.CountBySessionAndStatus(*session.ID, entity_crew.RSVPStatusGoing)
+		if err != nil {
+			return err
+		}
+		free = session.Capacity - going
+	}
+
+	for i := 0; i < free && i < len(waitlist); i++ {
+		waitlist[i].Status = entity_crew.RSVPStatusGoing
+		waitlist[i].WaitlistedAt = nil
+		waitlist[i].UpdatedAt = time.Now()
+		if err := repoRSVP.Update(waitlist[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *sessionRSVPUseCase) findSession(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.Crew, *entity_crew.MovieSession, error) {
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	session, err := u.repoSession.FindByIdAndCrew(sessionID, crewID)
+	if err != nil {
+		return nil, nil, ErrSessionNotFound
+	}
+	return crew, session, nil
+}
+
+func (u *sessionRSVPUseCase) Respond(crewID uuid.UUID, sessionID uuid.UUID, userID int, status string) (*entity_crew.SessionRSVP, error) {
+	if status != entity_crew.RSVPStatusGoing && status != entity_crew.RSVPStatusMaybe && status != entity_crew.RSVPStatusNotGoing {
+		return nil, fmt.Errorf("invalid status: must be '%s', '%s' or '%s'", entity_crew.RSVPStatusGoing, entity_crew.RSVPStatusMaybe, entity_crew.RSVPStatusNotGoing)
+	}
+
+	_, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.EndAt != nil && session.EndAt.Before(time.Now()) {
+		return nil, fmt.Errorf("session has already ended")
+	}
+
+	var rsvp *entity_crew.SessionRSVP
+	err = withSessionLock(u.transactionRSVP, sessionID, func(repo IRepositorySessionRSVP) error {
+		rsvp, err = saveResponse(repo, session, userID, status)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsvp, nil
+}
+
+// saveResponse saves a member's response to a session, putting them on the
+// waitlist when it is full. It must run with the session locked.
+func saveResponse(repoRSVP IRepositorySessionRSVP, session *entity_crew.MovieSession, userID int, status string) (*entity_crew.SessionRSVP, error) {
+	sessionID := *session.ID
+	rsvp, err := repoRSVP.FindBySessionAndUser(sessionID, userID)
+	isNew := err != nil
+	if isNew {
+		rsvp = &entity_crew.SessionRSVP{SessionID: session.ID, UserID: userID}
+	}
+	previous := rsvp.Status
+
+	switch {
+	case status != entity_crew.RSVPStatusGoing:
+		rsvp.Status = status
+		rsvp.WaitlistedAt = nil
+	case previous == entity_crew.RSVPStatusGoing || previous == entity_crew.RSVPStatusWaitlisted:
+		// Already going or queued; keep the place in line.
+	default:
+		rsvp.Status = entity_crew.RSVPStatusGoing
+		if session.Capacity > 0 {
+			going, err := repoRSVP.CountBySessionAndStatus(sessionID, entity_crew.RSVPStatusGoing)
+			if err != nil {
+				return nil, fmt.Errorf("could not check session capacity")
+			}
+			if going >= session.Capacity {
+				now := time.Now()
+				rsvp.Status = entity_crew.RSVPStatusWaitlisted
+				rsvp.WaitlistedAt = &now
+			}
+		}
+	}
+
+	if isNew {
+		err = repoRSVP.Create(rsvp)
+	} else {
+		rsvp.UpdatedAt = time.Now()
+		err = repoRSVP.Update(rsvp)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not save rsvp")
+	}
+
+	if previous == entity_crew.RSVPStatusGoing && rsvp.Status != entity_crew.RSVPStatusGoing {
+		if err := promoteWaitlist(repoRSVP, session); err != nil {
+			return nil, fmt.Errorf("could not promote waitlist")
+		}
+	}
+	return rsvp, nil
+}
+
+func (u *sessionRSVPUseCase) Withdraw(crewID uuid.UUID, sessionID uuid.UUID, userID int) error {
+	_, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return err
+	}
+
+	return withSessionLock(u.transactionRSVP, sessionID, func(repo IRepositorySessionRSVP) error {
+		rsvp, err := repo.FindBySessionAndUser(sessionID, userID)
+		if err != nil {
+			return fmt.Errorf("rsvp not found")
+		}
+		if err := repo.Delete(*rsvp.ID); err != nil {
+			return fmt.Errorf("could not delete rsvp")
+		}
+
+		if rsvp.Status == entity_crew.RSVPStatusGoing {
+			if err := promoteWaitlist(repo, session); err != nil {
+				return fmt.Errorf("could not promote waitlist")
+			}
+		}
+		return nil
+	})
+}
+
+func (u *sessionRSVPUseCase) GetSummary(crewID uuid.UUID, sessionID uuid.UUID, userID int) (*entity_crew.SessionRSVPSummary, error) {
+	crew, session, err := u.findSession(crewID, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	rsvps, err := u.repoRSVP.FindAllBySession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load rsvps")
+	}
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return nil, fmt.Errorf("could not load crew members")
+	}
+
+	summary := &entity_crew.SessionRSVPSummary{
+		SessionID: session.ID,
+		Capacity:  session.Capacity,
+		Responses: rsvps,
+	}
+	responded := map[int]bool{}
+	for _, rsvp := range rsvps {
+		responded[rsvp.UserID] = true
+		if rsvp.UserID == userID {
+			summary.MyStatus = rsvp.Status
+		}
+		switch rsvp.Status {
+		case entity_crew.RSVPStatusGoing:
+			summary.Going++
+		case entity_crew.RSVPStatusMaybe:
+			summary.Maybe++
+		case entity_crew.RSVPStatusNotGoing:
+			summary.NotGoing++
+		case entity_crew.RSVPStatusWaitlisted:
+			summary.Waitlisted++
+		}
+	}
+	for _, member := range members {
+		if !responded[member.UserID] {
+			summary.NoResponse = append(summary.NoResponse, member.User)
+		}
+	}
+	if session.Capacity > 0 {
+		spotsLeft := max(session.Capacity-summary.Going, 0)
+		summary.SpotsLeft = &spotsLeft
+	}
+	return summary, nil
+}
@@ -0,0 +1,256 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeMovieSessionRepository keeps sessions in memory.
+type fakeMovieSessionRepository struct {
+	sessions map[uuid.UUID]*entity_crew.MovieSession
+}
+
+func (r *fakeMovieSessionRepository) Create(session *entity_crew.MovieSession) error {
+	id := uuid.New()
+	session.ID = &id
+	copied := *session
+	r.sessions[id] = &copied
+	return nil
+}
+
+func (r *fakeMovieSessionRepository) FindByIdAndCrew(id uuid.UUID, crewID uuid.UUID) (*entity_crew.MovieSession, error) {
+	session, ok := r.sessions[id]
+	if !ok || *session.CrewID != crewID {
+		return nil, errors.New("record not found")
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (r *fakeMovieSessionRepository) FindAllByCrewWithFilter(crewID uuid.UUID, startDate, endDate *time.Time) ([]*entity_crew.MovieSession, error) {
+	sessions := []*entity_crew.MovieSession{}
+	for id := range r.sessions {
+		if session, err := r.FindByIdAndCrew(id, crewID); err == nil {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeMovieSessionRepository) Update(session *entity_crew.MovieSession) error {
+	copied := *session
+	r.sessions[*session.ID] = &copied
+	return nil
+}
+
+func (r *fakeMovieSessionRepository) Delete(id uuid.UUID) (bool, error) {
+	delete(r.sessions, id)
+	return true, nil
+}
+
+// fakeSessionRSVPRepository keeps responses in memory, in the order they
+// were created. Its transaction restores them when fn fails.
+type fakeSessionRSVPRepository struct {
+	rsvps []*entity_crew.SessionRSVP
+}
+
+func (r *fakeSessionRSVPRepository) transaction(fn func(repo IRepositorySessionRSVP) error) error {
+	snapshot := []*entity_crew.SessionRSVP{}
+	for _, rsvp := range r.rsvps {
+		copied := *rsvp
+		snapshot = append(snapshot, &copied)
+	}
+	if err := fn(r); err != nil {
+		r.rsvps = snapshot
+		return err
+	}
+	return nil
+}
+
+func (r *fakeSessionRSVPRepository) find(match func(rsvp *entity_crew.SessionRSVP) bool) []*entity_crew.SessionRSVP {
+	rsvps := []*entity_crew.SessionRSVP{}
+	for _, rsvp := range r.rsvps {
+		if match(rsvp) {
+			copied := *rsvp
+			rsvps = append(rsvps, &copied)
+		}
+	}
+	return rsvps
+}
+
+func (r *fakeSessionRSVPRepository) Create(rsvp *entity_crew.SessionRSVP) error {
+	id := uuid.New()
+	rsvp.ID = &id
+	copied := *rsvp
+	r.rsvps = append(r.rsvps, &copied)
+	return nil
+}
+
+func (r *fakeSessionRSVPRepository) FindBySessionAndUser(sessionID uuid.UUID, userID int) (*entity_crew.SessionRSVP, error) {
+	rsvps := r.find(func(rsvp *entity_crew.SessionRSVP) bool { return *rsvp.SessionID == sessionID && rsvp.UserID == userID })
+	if len(rsvps) == 0 {
+		return nil, errors.New("record not found")
+	}
+	return rsvps[0], nil
+}
+
+func (r *fakeSessionRSVPRepository) FindAllBySession(sessionID uuid.UUID) ([]*entity_crew.SessionRSVP, error) {
+	return r.find(func(rsvp *entity_crew.SessionRSVP) bool { return *rsvp.SessionID == sessionID }), nil
+}
+
+func (r *fakeSessionRSVPRepository) FindWaitlistBySession(sessionID uuid.UUID) ([]*entity_crew.SessionRSVP, error) {
+	waitlist := r.find(func(rsvp *entity_crew.SessionRSVP) bool {
+		return *rsvp.SessionID == sessionID && rsvp.Status == entity_crew.RSVPStatusWaitlisted
+	})
+	sort.SliceStable(waitlist, func(i, j int) bool { return waitlist[i].WaitlistedAt.Before(*waitlist[j].WaitlistedAt) })
+	return waitlist, nil
+}
+
+func (r *fakeSessionRSVPRepository) CountBySessionAndStatus(sessionID uuid.UUID, status string) (int, error) {
+	return len(r.find(func(rsvp *entity_crew.SessionRSVP) bool { return *rsvp.SessionID == sessionID && rsvp.Status == status })), nil
+}
+
+func (r *fakeSessionRSVPRepository) Update(rsvp *entity_crew.SessionRSVP) error {
+	for i := range r.rsvps {
+		if *r.rsvps[i].ID == *rsvp.ID {
+			copied := *rsvp
+			r.rsvps[i] = &copied
+			return nil
+		}
+	}
+	return errors.New("record not found")
+}
+
+func (r *fakeSessionRSVPRepository) Delete(id uuid.UUID) error {
+	for i := range r.rsvps {
+		if *r.rsvps[i].ID == id {
+			r.rsvps = append(r.rsvps[:i], r.rsvps[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *fakeSessionRSVPRepository) LockSession(sessionID uuid.UUID) error {
+	return nil
+}
+
+func TestSessionWaitlist(t *testing.T) {
+	// The session takes two members: the owner and the admin are going, the
+	// member and then the other member are on the waitlist
+	full := map[int]string{
+		userOwner:       entity_crew.RSVPStatusGoing,
+		userAdmin:       entity_crew.RSVPStatusGoing,
+		userMember:      entity_crew.RSVPStatusWaitlisted,
+		userOtherMember: entity_crew.RSVPStatusWaitlisted,
+	}
+	withCapacity := func(capacity int) func(rsvp IUseCaseSessionRSVP, sessions IUseCaseMovieSession, session *entity_crew.MovieSession) error {
+		return func(rsvp IUseCaseSessionRSVP, sessions IUseCaseMovieSession, session *entity_crew.MovieSession) error {
+			session.Capacity = capacity
+			return sessions.Update(session, *session.CrewID, userOwner)
+		}
+	}
+
+	tests := []struct {
+		name string
+		run  func(rsvp IUseCaseSessionRSVP, sessions IUseCaseMovieSession, session *entity_crew.MovieSession) error
+		want map[int]string // Responses afterwards, "" for none
+	}{
+		{
+			name: "going member withdrawing promotes the first of the waitlist",
+			run: func(rsvp IUseCaseSessionRSVP, sessions IUseCaseMovieSession, session *entity_crew.MovieSession) error {
+				return rsvp.Withdraw(*session.CrewID, *session.ID, userOwner)
+			},
+			want: map[int]string{userOwner: "", userAdmin: "going", userMember: "going", userOtherMember: "waitlisted"},
+		},
+		{
+			name: "going member answering not going promotes the first of the waitlist",
+			run: func(rsvp IUseCaseSessionRSVP, sessions IUseCaseMovieSession, session *entity_crew.MovieSession) error {
+				_, err := rsvp.Respond(*session.CrewID, *session.ID, userAdmin, entity_crew.RSVPStatusNotGoing)
+				return err
+			},
+			want: map[int]string{userOwner: "going", userAdmin: "not_going", userMember: "going", userOtherMember: "waitlisted"},
+		},
+		{
+			name: "waitlisted member withdrawing frees no spot",
+			run: func(rsvp IUseCaseSessionRSVP, sessions IUseCaseMovieSession, session *entity_crew.MovieSession) error {
+				return rsvp.Withdraw(*session.CrewID, *session.ID, userMember)
+			},
+			want: map[int]string{userOwner: "going", userAdmin: "going", userMember: "", userOtherMember: "waitlisted"},
+		},
+		{
+			name: "answering going again keeps the place in line",
+			run: func(rsvp IUseCaseSessionRSVP, sessions IUseCaseMovieSession, session *entity_crew.MovieSession) error {
+				if _, err := rsvp.Respond(*session.CrewID, *session.ID, userMember, entity_crew.RSVPStatusGoing); err != nil {
+					return err
+				}
+				return rsvp.Withdraw(*session.CrewID, *session.ID, userOwner)
+			},
+			want: map[int]string{userOwner: "", userAdmin: "going", userMember: "going", userOtherMember: "waitlisted"},
+		},
+		{
+			name: "raised capacity promotes as many as the new spots",
+			run:  withCapacity(3),
+			want: map[int]string{userOwner: "going", userAdmin: "going", userMember: "going", userOtherMember: "waitlisted"},
+		},
+		{
+			name: "removed capacity promotes the whole waitlist",
+			run:  withCapacity(0),
+			want: map[int]string{userOwner: "going", userAdmin: "going", userMember: "going", userOtherMember: "going"},
+		},
+		{
+			name: "lowered capacity keeps everybody going",
+			run:  withCapacity(1),
+			want: full,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, repoCrew, repoMember, _ := newCrewFixture()
+			repoSession := &fakeMovieSessionRepository{sessions: map[uuid.UUID]*entity_crew.MovieSession{}}
+			repoRSVP := &fakeSessionRSVPRepository{}
+			rsvp := NewSessionRSVPUseCase(repoCrew, repoMember, repoSession, repoRSVP, repoRSVP.transaction)
+			sessions := NewMovieSessionUseCase(repoCrew, repoMember, repoSession, repoRSVP, repoRSVP.transaction, nil, nil)
+
+			startAt, endAt := time.Now().Add(24*time.Hour), time.Now().Add(27*time.Hour)
+			session := &entity_crew.MovieSession{CrewID: crew.ID, Title: "Heat", StartAt: &startAt, EndAt: &endAt, LocationType: entity_crew.LocationTypeInPerson, Location: "Ana's place", Capacity: 2, OrganizerID: userOwner}
+			_ = repoSession.Create(session)
+			for _, userID := range []int{userOwner, userAdmin, userMember, userOtherMember} {
+				if _, err := rsvp.Respond(*crew.ID, *session.ID, userID, entity_crew.RSVPStatusGoing); err != nil {
+					t.Fatalf("Respond() error = %v", err)
+				}
+			}
+			if got := responses(repoRSVP); !reflect.DeepEqual(got, full) {
+				t.Fatalf("responses = %v, want %v", got, full)
+			}
+
+			if err := tt.run(rsvp, sessions, session); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			want := map[int]string{}
+			for userID, status := range tt.want {
+				if status != "" {
+					want[userID] = status
+				}
+			}
+			if got := responses(repoRSVP); !reflect.DeepEqual(got, want) {
+				t.Errorf("responses = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func responses(repoRSVP *fakeSessionRSVPRepository) map[int]string {
+	statuses := map[int]string{}
+	for _, rsvp := range repoRSVP.rsvps {
+		statuses[rsvp.UserID] = rsvp.Status
+	}
+	return statuses
+}
@@ -0,0 +1,193 @@
+package usecase_crew
+
+import (
+	entity_accounts "app/entity/accounts"
+	entity_crew "app/entity/crew"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultSuggestionLimit    = 5
+	MaxSuggestionLimit        = 20
+	DefaultPreferredFromHour  = 18
+	DefaultPreferredToHour    = 23
+	suggestionStep            = 30 * time.Minute
+	suggestionMemberWeight    = 100.0
+	suggestionHourWeight      = 20.0
+	suggestionWeekdayWeight   = 10.0
+	maxSuggestionDuration     = 24 * time.Hour
+	minimumSuggestionDuration = 15 * time.Minute
+)
+
+// covers reports whether one of the sorted, merged intervals contains [start, end).
+func covers(intervals []interval, start, end time.Time) bool {
+	i := sort.Search(len(intervals), func(i int) bool { return intervals[i].end.After(start) })
+	return i < len(intervals) && !intervals[i].start.After(start) && !intervals[i].end.Before(end)
+}
+
+// preferredHourRatio returns the fraction of [start, end) that falls inside
+// the preferred local hours. A window such as 22h-2h wraps past midnight.
+func preferredHourRatio(start, end time.Time, fromHour, toHour int, loc *time.Location) float64 {
+	if fromHour == toHour {
+		return 0
+	}
+	inside := time.Duration(0)
+	for t := start; t.Before(end); {
+		local := t.In(loc)
+		next := time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, loc)
+		if next.After(end) {
+			next = end
+		}
+		hour := local.Hour()
+		if (fromHour < toHour && hour >= fromHour && hour < toHour) ||
+			(fromHour > toHour && (hour >= fromHour || hour < toHour)) {
+			inside += next.Sub(t)
+		}
+		t = next
+	}
+	return float64(inside) / float64(end.Sub(start))
+}
+
+func memberNames(users []*entity_accounts.User) string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		if user != nil {
+			names = append(names, user.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// scoreSuggestions evaluates every candidate start in the window and returns
+// the best non-overlapping ones.
+func scoreSuggestions(free map[int][]interval, members []*entity_crew.CrewMember, options SuggestionOptions) []*entity_crew.SessionSuggestion {
+	merged := map[int][]interval{}
+	for _, member := range members {
+		merged[member.UserID] = mergeIntervals(free[member.UserID], options.From, options.To)
+	}
+	weekdays := map[time.Weekday]bool{}
+	for _, day := range options.PreferredWeekdays {
+		weekdays[day] = true
+	}
+
+	start := options.From.Truncate(suggestionStep)
+	if start.Before(options.From) {
+		start = start.Add(suggestionStep)
+	}
+
+	candidates := []*entity_crew.SessionSuggestion{}
+	for ; !start.Add(options.Duration).After(options.To); start = start.Add(suggestionStep) {
+		end := start.Add(options.Duration)
+		suggestion := &entity_crew.SessionSuggestion{StartAt: start, EndAt: end}
+		for _, member := range members {
+			if covers(merged[member.UserID], start, end) {
+				suggestion.AvailableMembers = append(suggestion.AvailableMembers, member.User)
+			} else {
+				suggestion.MissingMembers = append(suggestion.MissingMembers, member.User)
+			}
+		}
+		suggestion.AvailableCount = len(suggestion.AvailableMembers)
+		if suggestion.AvailableCount == 0 || suggestion.AvailableCount < options.MinMembers {
+			continue
+		}
+
+		local := start.In(options.Location)
+		hourRatio := preferredHourRatio(start, end, options.PreferredFromHour, options.PreferredToHour, options.Location)
+		suggestion.Score = suggestionMemberWeight * float64(suggestion.AvailableCount) / float64(len(members))
+		suggestion.Score += suggestionHourWeight * hourRatio
+
+		if len(suggestion.MissingMembers) == 0 {
+			suggestion.Reasons = append(suggestion.Reasons, "Everyone is available")
+		} else {
+			suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("%d of %d members available; missing: %s", suggestion.AvailableCount, len(members), memberNames(suggestion.MissingMembers)))
+		}
+		if hourRatio == 1 {
+			suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("Entirely within preferred hours (%02d:00-%02d:00)", options.PreferredFromHour, options.PreferredToHour))
+		} else if hourRatio > 0 {
+			suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("%.0f%% within preferred hours (%02d:00-%02d:00)", hourRatio*100, options.PreferredFromHour, options.PreferredToHour))
+		}
+		if weekdays[local.Weekday()] {
+			suggestion.Score += suggestionWeekdayWeight
+			suggestion.Reasons = append(suggestion.Reasons, fmt.Sprintf("%s is a preferred weekday", local.Weekday()))
+		}
+		suggestion.Score = math.Round(suggestion.Score*100) / 100
+		candidates = append(candidates, suggestion)
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].Score != candidates[b].Score {
+			return candidates[a].Score > candidates[b].Score
+		}
+		return candidates[a].StartAt.Before(candidates[b].StartAt)
+	})
+
+	// Skip candidates overlapping a better one so suggestions are distinct slots.
+	picked := []*entity_crew.SessionSuggestion{}
+	for _, candidate := range candidates {
+		if len(picked) == options.Limit {
+			break
+		}
+		overlaps := false
+		for _, p := range picked {
+			if candidate.StartAt.Before(p.EndAt) && candidate.EndAt.After(p.StartAt) {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			picked = append(picked, candidate)
+		}
+	}
+	return picked
+}
+
+func (u *crewAvailabilityUseCase) SuggestSlots(crewID uuid.UUID, userID int, options SuggestionOptions) ([]*entity_crew.SessionSuggestion, error) {
+	if !options.To.After(options.From) {
+		return nil, fmt.Errorf("'to' must be after 'from'")
+	}
+	if options.To.Sub(options.From) > MaxAvailabilityRange {
+		return nil, fmt.Errorf("date range must not exceed 366 days")
+	}
+	if options.Duration < minimumSuggestionDuration || options.Duration > maxSuggestionDuration {
+		return nil, fmt.Errorf("duration must be between 15 minutes and 24 hours")
+	}
+	if options.Limit == 0 {
+		options.Limit = DefaultSuggestionLimit
+	}
+	if options.Limit < 1 || options.Limit > MaxSuggestionLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", MaxSuggestionLimit)
+	}
+	if options.PreferredFromHour < 0 || options.PreferredFromHour > 23 || options.PreferredToHour < 0 || options.PreferredToHour > 24 {
+		return nil, fmt.Errorf("preferred hours must be between 0 and 24")
+	}
+	if options.MinMembers < 0 {
+		return nil, fmt.Errorf("min_members must not be negative")
+	}
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+
+	crew, _, err := findMembership(u.repoCrew, u.repoMember, crewID, userID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := findAllMembers(u.repoMember, crew)
+	if err != nil {
+		return nil, fmt.Errorf("could not load crew members")
+	}
+	if options.MinMembers > len(members) {
+		return nil, fmt.Errorf("min_members must be between 1 and %d", len(members))
+	}
+
+	free, err := loadFreeIntervals(u.repoDayOff, members, options.From, options.To)
+	if err != nil {
+		return nil, err
+	}
+	return scoreSuggestions(free, members, options), nil
+}
@@ -0,0 +1,86 @@
+package usecase_crew
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSuggestSlots(t *testing.T) {
+	tests := []struct {
+		name        string
+		options     SuggestionOptions
+		want        []string // "<start>-<end> <score> <available members>"
+		wantReasons []string // Reasons of the best suggestion
+		wantErr     bool
+	}{
+		{
+			name:        "more members score higher",
+			options:     SuggestionOptions{Duration: 2 * time.Hour, Limit: 3},
+			want:        []string{"14-16 75 Ana Bruno Carla", "12-14 50 Ana Bruno", "09-11 25 Ana"},
+			wantReasons: []string{"3 of 4 members available; missing: Davi"},
+		},
+		{
+			name:    "preferred hours beat an earlier slot with as many members",
+			options: SuggestionOptions{Duration: 2 * time.Hour, Limit: 3, PreferredFromHour: 18, PreferredToHour: 23},
+			want:    []string{"14-16 75 Ana Bruno Carla", "12-14 50 Ana Bruno", "18-20 45 Bruno"},
+		},
+		{
+			name:    "preferred hours in the time zone of the crew",
+			options: SuggestionOptions{Duration: 2 * time.Hour, Limit: 3, PreferredFromHour: 15, PreferredToHour: 20, Location: time.FixedZone("-03", -3*60*60)},
+			want:    []string{"14-16 75 Ana Bruno Carla", "12-14 50 Ana Bruno", "18-20 45 Bruno"},
+		},
+		{
+			name:        "preferred weekday adds to every slot of the day",
+			options:     SuggestionOptions{Duration: 2 * time.Hour, Limit: 1, PreferredWeekdays: []time.Weekday{time.Saturday}},
+			want:        []string{"14-16 85 Ana Bruno Carla"},
+			wantReasons: []string{"3 of 4 members available; missing: Davi", "Saturday is a preferred weekday"},
+		},
+		{
+			name:        "partly within preferred hours",
+			options:     SuggestionOptions{Duration: 2 * time.Hour, Limit: 1, MinMembers: 3, PreferredFromHour: 15, PreferredToHour: 18},
+			want:        []string{"14-16 85 Ana Bruno Carla"},
+			wantReasons: []string{"3 of 4 members available; missing: Davi", "50% within preferred hours (15:00-18:00)"},
+		},
+		{
+			name:    "slots below the minimum are left out",
+			options: SuggestionOptions{Duration: time.Hour, MinMembers: 3},
+			want:    []string{"14-15 75 Ana Bruno Carla", "15-16 75 Ana Bruno Carla"},
+		},
+		{name: "negative minimum", options: SuggestionOptions{Duration: time.Hour, MinMembers: -1}, wantErr: true},
+		{name: "minimum above the crew size", options: SuggestionOptions{Duration: time.Hour, MinMembers: 5}, wantErr: true},
+		{name: "duration too short", options: SuggestionOptions{Duration: 10 * time.Minute}, wantErr: true},
+		{name: "limit too high", options: SuggestionOptions{Duration: time.Hour, Limit: MaxSuggestionLimit + 1}, wantErr: true},
+		{name: "preferred hour out of range", options: SuggestionOptions{Duration: time.Hour, PreferredToHour: 25}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crew, usecase := newAvailabilityFixture()
+			options := tt.options
+			options.From, options.To = at(0), at(24)
+
+			suggestions, err := usecase.SuggestSlots(*crew.ID, userMember, options)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SuggestSlots() = %v, want an error", suggestions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SuggestSlots() error = %v", err)
+			}
+			got := []string{}
+			for _, suggestion := range suggestions {
+				got = append(got, fmt.Sprintf("%02d-%02d %g%s", suggestion.StartAt.Hour(), suggestion.EndAt.Hour(), suggestion.Score, formatMembers(suggestion.AvailableMembers)))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggestions = %v, want %v", got, tt.want)
+			}
+			if tt.wantReasons != nil && len(suggestions) > 0 && !reflect.DeepEqual(suggestions[0].Reasons, tt.wantReasons) {
+				t.Errorf("reasons = %q, want %q", suggestions[0].Reasons, tt.wantReasons)
+			}
+		})
+	}
+}
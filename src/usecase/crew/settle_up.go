@@ -0,0 +1,137 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"math/bits"
+	"sort"
+)
+
+// maxExactSettlement bounds the number of non-zero balances solved exactly;
+// the exact search is exponential in that number.
+const maxExactSettlement = 16
+
+type transfer struct {
+	from   int
+	to     int
+	amount int64
+}
+
+// computeBalances nets every expense share and confirmed payment of a crew
+// into a balance per user. Positive balances are owed money.
+func computeBalances(expenses []*entity_crew.SessionExpense, payments []*entity_crew.CrewPayment) map[int]int64 {
+	balances := map[int]int64{}
+	for _, expense := range expenses {
+		for _, share := range expense.Shares {
+			if share.DebtorID == expense.PayerID {
+				continue
+			}
+			balances[expense.PayerID] += share.AmountCents
+			balances[share.DebtorID] -= share.AmountCents
+		}
+	}
+	for _, payment := range payments {
+		if payment.Status != entity_crew.PaymentStatusConfirmed {
+			continue
+		}
+		balances[payment.FromUserID] += payment.AmountCents
+		balances[payment.ToUserID] -= payment.AmountCents
+	}
+	return balances
+}
+
+// settleGroup pays off a zero-sum group of balances greedily, matching the
+// largest debtor with the largest creditor. A group of k users needs at most
+// k-1 transfers.
+func settleGroup(users []int, balances map[int]int64) []transfer {
+	type position struct {
+		userID int
+		amount int64
+	}
+	creditors, debtors := []position{}, []position{}
+	for _, userID := range users {
+		if balances[userID] > 0 {
+			creditors = append(creditors, position{userID, balances[userID]})
+		} else if balances[userID] < 0 {
+			debtors = append(debtors, position{userID, -balances[userID]})
+		}
+	}
+	sort.Slice(creditors, func(a, b int) bool { return creditors[a].amount > creditors[b].amount })
+	sort.Slice(debtors, func(a, b int) bool { return debtors[a].amount > debtors[b].amount })
+
+	transfers := []transfer{}
+	for c, d := 0, 0; c < len(creditors) && d < len(debtors); {
+		amount := min(creditors[c].amount, debtors[d].amount)
+		transfers = append(transfers, transfer{from: debtors[d].userID, to: creditors[c].userID, amount: amount})
+		creditors[c].amount -= amount
+		debtors[d].amount -= amount
+		if creditors[c].amount == 0 {
+			c++
+		}
+		if debtors[d].amount == 0 {
+			d++
+		}
+	}
+	return transfers
+}
+
+// settleUp returns a plan that zeroes every balance with the fewest
+// transfers. n non-zero balances need n-g transfers, where g is the largest
+// number of disjoint zero-sum groups they can be split into, so the exact
+// search maximizes g over subsets. Larger crews fall back to one greedy group.
+func settleUp(balances map[int]int64) []transfer {
+	users := []int{}
+	for userID, balance := range balances {
+		if balance != 0 {
+			users = append(users, userID)
+		}
+	}
+	sort.Ints(users)
+	if len(users) > maxExactSettlement {
+		return settleGroup(users, balances)
+	}
+
+	n := len(users)
+	full := 1<<n - 1
+	sums := make([]int64, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		low := bits.TrailingZeros(uint(mask))
+		sums[mask] = sums[mask&(mask-1)] + balances[users[low]]
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && groups[mask^(1<<i)] > groups[mask] {
+				groups[mask] = groups[mask^(1<<i)]
+			}
+		}
+		if sums[mask] == 0 {
+			groups[mask]++
+		}
+	}
+
+	// Peel users off one at a time along an optimal path; each time the
+	// remaining set sums to zero, the peeled users form one group.
+	transfers := []transfer{}
+	group := []int{}
+	for mask := full; mask != 0; {
+		next := -1
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			bonus := 0
+			if sums[mask] == 0 {
+				bonus = 1
+			}
+			if groups[mask^(1<<i)]+bonus == groups[mask] {
+				next = i
+				break
+			}
+		}
+		group = append(group, users[next])
+		mask ^= 1 << next
+		if sums[mask] == 0 {
+			transfers = append(transfers, settleGroup(group, balances)...)
+			group = []int{}
+		}
+	}
+	return transfers
+}
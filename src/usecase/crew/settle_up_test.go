@@ -0,0 +1,119 @@
+package usecase_crew
+
+import (
+	entity_crew "app/entity/crew"
+	"reflect"
+	"testing"
+)
+
+func TestComputeBalances(t *testing.T) {
+	expenses := []*entity_crew.SessionExpense{
+		{PayerID: 1, AmountCents: 3000, Shares: []*entity_crew.ExpenseShare{
+			{DebtorID: 1, AmountCents: 1000},
+			{DebtorID: 2, AmountCents: 1000},
+			{DebtorID: 3, AmountCents: 1000},
+		}},
+		{PayerID: 2, AmountCents: 600, Shares: []*entity_crew.ExpenseShare{
+			{DebtorID: 1, AmountCents: 300},
+			{DebtorID: 2, AmountCents: 300},
+		}},
+	}
+	payments := []*entity_crew.CrewPayment{
+		{FromUserID: 3, ToUserID: 1, AmountCents: 400, Status: entity_crew.PaymentStatusConfirmed},
+		{FromUserID: 2, ToUserID: 1, AmountCents: 700, Status: entity_crew.PaymentStatusPaid},
+	}
+
+	got := computeBalances(expenses, payments)
+	want := map[int]int64{1: 2000 - 300 - 400, 2: -1000 + 300, 3: -1000 + 400}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("balances = %v, want %v", got, want)
+	}
+}
+
+func TestSettleUp(t *testing.T) {
+	tests := []struct {
+		name      string
+		balances  map[int]int64
+		transfers int
+	}{
+		{
+			name:      "nothing to settle",
+			balances:  map[int]int64{1: 0, 2: 0},
+			transfers: 0,
+		},
+		{
+			name:      "one debt",
+			balances:  map[int]int64{1: 500, 2: -500},
+			transfers: 1,
+		},
+		{
+			name:      "one creditor and many debtors",
+			balances:  map[int]int64{1: 900, 2: -300, 3: -300, 4: -300},
+			transfers: 3,
+		},
+		{
+			name:      "two independent pairs",
+			balances:  map[int]int64{1: 500, 2: 300, 3: -500, 4: -300},
+			transfers: 2,
+		},
+		{
+			name:      "zero-sum groups that greedy matching misses",
+			balances:  map[int]int64{1: 400, 2: 300, 3: -200, 4: -200, 5: -300},
+			transfers: 3,
+		},
+		{
+			name:      "three groups",
+			balances:  map[int]int64{1: 100, 2: -100, 3: 250, 4: -250, 5: 700, 6: -400, 7: -300},
+			transfers: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers := settleUp(tt.balances)
+			if len(transfers) != tt.transfers {
+				t.Errorf("got %d transfers %v, want %d", len(transfers), transfers, tt.transfers)
+			}
+			assertSettled(t, tt.balances, transfers)
+		})
+	}
+}
+
+func TestSettleUpLargeCrew(t *testing.T) {
+	// Past maxExactSettlement the plan is greedy, but it still settles
+	balances := map[int]int64{}
+	var total int64
+	for userID := 1; userID <= maxExactSettlement+4; userID++ {
+		balances[userID] = int64(userID * 100)
+		total += balances[userID]
+	}
+	balances[100] = -total
+
+	transfers := settleUp(balances)
+	if len(transfers) > len(balances)-1 {
+		t.Errorf("got %d transfers, want at most %d", len(transfers), len(balances)-1)
+	}
+	assertSettled(t, balances, transfers)
+}
+
+// assertSettled checks that the transfers move positive amounts and zero
+// every balance.
+func assertSettled(t *testing.T, balances map[int]int64, transfers []transfer) {
+	t.Helper()
+	left := map[int]int64{}
+	for userID, balance := range balances {
+		left[userID] = balance
+	}
+	for _, tr := range transfers {
+		if tr.amount <= 0 {
+			t.Errorf("transfer %v moves a non-positive amount", tr)
+		}
+		left[tr.from] += tr.amount
+		left[tr.to] -= tr.amount
+	}
+	for userID, balance := range left {
+		if balance != 0 {
+			t.Errorf("user %d ends with balance %d", userID, balance)
+		}
+	}
+}
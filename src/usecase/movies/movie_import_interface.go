@@ -0,0 +1,16 @@
+package usecase_movies
+
+import "io"
+
+// ImportResult counts what an import did with the records of a file.
+type ImportResult struct {
+	Read    int // Records of the file
+	Skipped int // Malformed records, adult titles, non movies and conflicting IDs
+	Created int
+	Updated int
+}
+
+type IUseCaseMovieImport interface {
+	ImportTMDB(r io.Reader) (*ImportResult, error)
+	ImportIMDb(r io.Reader) (*ImportResult, error)
+}
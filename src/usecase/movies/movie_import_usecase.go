@@ -0,0 +1,359 @@
+package usecase_movies
+
+import (
+	entity_movies "app/entity/movies"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	importBatchSize = 1000
+	maxLineSize     = 1024 * 1024
+	imdbNull        = `\N`
+)
+
+type movieImportUseCase struct {
+	repo IRepositoryMovie
+}
+
+func NewMovieImportUseCase(repo IRepositoryMovie) IUseCaseMovieImport {
+	return &movieImportUseCase{repo: repo}
+}
+
+// tmdbRecord is one line of a TMDB JSONL file. Daily exports only carry the
+// ID and original title; files of movie details carry everything else.
+type tmdbRecord struct {
+	ID            int             `json:"id"`
+	IMDbID        string          `json:"imdb_id"`
+	Title         string          `json:"title"`
+	OriginalTitle string          `json:"original_title"`
+	ReleaseDate   string          `json:"release_date"`
+	Runtime       int             `json:"runtime"`
+	Genres        json.RawMessage `json:"genres"`
+	PosterPath    string          `json:"poster_path"`
+	Adult         bool            `json:"adult"`
+}
+
+// tmdbGenres accepts both [{"id": 18, "name": "Drama"}] and ["Drama"].
+func tmdbGenres(raw json.RawMessage) []string {
+	genres := []string{}
+	if len(raw) == 0 {
+		return genres
+	}
+
+	var objects []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &objects); err == nil {
+		for _, genre := range objects {
+			genres = append(genres, genre.Name)
+		}
+		return cleanGenres(genres)
+	}
+	if err := json.Unmarshal(raw, &genres); err == nil {
+		return cleanGenres(genres)
+	}
+	return []string{}
+}
+
+func cleanGenres(genres []string) []string {
+	result := []string{}
+	for _, genre := range genres {
+		if genre = strings.TrimSpace(genre); genre != "" {
+			result = append(result, genre)
+		}
+	}
+	return result
+}
+
+func optionalString(value string) *string {
+	if value = strings.TrimSpace(value); value == "" {
+		return nil
+	}
+	return &value
+}
+
+func optionalInt(value int) *int {
+	if value <= 0 {
+		return nil
+	}
+	return &value
+}
+
+func (r *tmdbRecord) movie() *entity_movies.Movie {
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		title = strings.TrimSpace(r.OriginalTitle)
+	}
+	movie := &entity_movies.Movie{
+		TMDBID:         optionalInt(r.ID),
+		IMDbID:         optionalString(r.IMDbID),
+		Title:          title,
+		OriginalTitle:  strings.TrimSpace(r.OriginalTitle),
+		RuntimeMinutes: optionalInt(r.Runtime),
+		Genres:         tmdbGenres(r.Genres),
+		PosterPath:     optionalString(r.PosterPath),
+	}
+	if len(r.ReleaseDate) >= 4 {
+		if year, err := strconv.Atoi(r.ReleaseDate[:4]); err == nil {
+			movie.Year = optionalInt(year)
+		}
+	}
+	return movie
+}
+
+func newScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	return scanner
+}
+
+func (u *movieImportUseCase) ImportTMDB(r io.Reader) (*ImportResult, error) {
+	result := &ImportResult{}
+	batch := []*entity_movies.Movie{}
+
+	scanner := newScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		result.Read++
+
+		var record tmdbRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil || record.ID <= 0 || record.Adult {
+			result.Skipped++
+			continue
+		}
+		movie := record.movie()
+		if movie.Title == "" {
+			result.Skipped++
+			continue
+		}
+
+		batch = append(batch, movie)
+		if len(batch) == importBatchSize {
+			if err := u.saveBatch(batch, result); err != nil {
+				return result, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("could not read tmdb file: %v", err)
+	}
+	if err := u.saveBatch(batch, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// ImportIMDb reads an IMDb title.basics.tsv file, keeping movies and TV
+// movies. Fields are tab separated, unquoted, and \N means null.
+func (u *movieImportUseCase) ImportIMDb(r io.Reader) (*ImportResult, error) {
+	result := &ImportResult{}
+	batch := []*entity_movies.Movie{}
+
+	scanner := newScanner(r)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return result, fmt.Errorf("could not read imdb file: %v", err)
+		}
+		return result, fmt.Errorf("imdb file is empty")
+	}
+	columns := map[string]int{}
+	for i, name := range strings.Split(scanner.Text(), "\t") {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"tconst", "titleType", "primaryTitle", "originalTitle", "isAdult", "startYear", "runtimeMinutes", "genres"} {
+		if _, ok := columns[name]; !ok {
+			return result, fmt.Errorf("imdb file has no %s column", name)
+		}
+	}
+
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) == 1 && fields[0] == "" {
+			continue
+		}
+		result.Read++
+
+		get := func(name string) string {
+			if i := columns[name]; i < len(fields) && fields[i] != imdbNull {
+				return fields[i]
+			}
+			return ""
+		}
+		if len(fields) != len(columns) || get("isAdult") == "1" || !strings.HasPrefix(get("tconst"), "tt") {
+			result.Skipped++
+			continue
+		}
+		if titleType := get("titleType"); titleType != "movie" && titleType != "tvMovie" {
+			result.Skipped++
+			continue
+		}
+
+		movie := &entity_movies.Movie{
+			IMDbID:        optionalString(get("tconst")),
+			Title:         strings.TrimSpace(get("primaryTitle")),
+			OriginalTitle: strings.TrimSpace(get("originalTitle")),
+			Genres:        cleanGenres(strings.Split(get("genres"), ",")),
+		}
+		if year, err := strconv.Atoi(get("startYear")); err == nil {
+			movie.Year = optionalInt(year)
+		}
+		if runtime, err := strconv.Atoi(get("runtimeMinutes")); err == nil {
+			movie.RuntimeMinutes = optionalInt(runtime)
+		}
+		if movie.Title == "" {
+			result.Skipped++
+			continue
+		}
+
+		batch = append(batch, movie)
+		if len(batch) == importBatchSize {
+			if err := u.saveBatch(batch, result); err != nil {
+				return result, err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("could not read imdb file: %v", err)
+	}
+	if err := u.saveBatch(batch, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// mergeMovie copies the non empty fields of src into dst. External IDs are
+// only filled in, never replaced, so a movie keeps the identity it has.
+func mergeMovie(dst *entity_movies.Movie, src *entity_movies.Movie) {
+	if dst.TMDBID == nil {
+		dst.TMDBID = src.TMDBID
+	}
+	if dst.IMDbID == nil {
+		dst.IMDbID = src.IMDbID
+	}
+	if src.Title != "" {
+		dst.Title = src.Title
+	}
+	if src.OriginalTitle != "" {
+		dst.OriginalTitle = src.OriginalTitle
+	}
+	if src.Year != nil {
+		dst.Year = src.Year
+	}
+	if src.RuntimeMinutes != nil {
+		dst.RuntimeMinutes = src.RuntimeMinutes
+	}
+	if len(src.Genres) > 0 {
+		dst.Genres = src.Genres
+	}
+	if src.PosterPath != nil {
+		dst.PosterPath = src.PosterPath
+	}
+}
+
+// conflictingIDs reports whether the TMDB and IMDb IDs of a record point to
+// different movies: each matches another movie, or the movie with the IMDb
+// ID already has another TMDB ID.
+func conflictingIDs(movie *entity_movies.Movie, tmdbMatch *entity_movies.Movie, imdbMatch *entity_movies.Movie) bool {
+	if imdbMatch == nil {
+		return false
+	}
+	if tmdbMatch != nil {
+		return tmdbMatch != imdbMatch
+	}
+	return movie.TMDBID != nil && imdbMatch.TMDBID != nil && *movie.TMDBID != *imdbMatch.TMDBID
+}
+
+// saveBatch upserts the movies by external ID: a record updates the movie
+// with its TMDB ID, else the one with its IMDb ID, else creates a new one.
+func (u *movieImportUseCase) saveBatch(batch []*entity_movies.Movie, result *ImportResult) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	tmdbIDs := []int{}
+	imdbIDs := []string{}
+	for _, movie := range batch {
+		if movie.TMDBID != nil {
+			tmdbIDs = append(tmdbIDs, *movie.TMDBID)
+		}
+		if movie.IMDbID != nil {
+			imdbIDs = append(imdbIDs, *movie.IMDbID)
+		}
+	}
+	existing, err := u.repo.FindAllByExternalIDs(tmdbIDs, imdbIDs)
+	if err != nil {
+		return fmt.Errorf("could not load movies")
+	}
+
+	byTMDB := map[int]*entity_movies.Movie{}
+	byIMDb := map[string]*entity_movies.Movie{}
+	index := func(movie *entity_movies.Movie) {
+		if movie.TMDBID != nil {
+			byTMDB[*movie.TMDBID] = movie
+		}
+		if movie.IMDbID != nil {
+			byIMDb[*movie.IMDbID] = movie
+		}
+	}
+	for _, movie := range existing {
+		index(movie)
+	}
+
+	created := []*entity_movies.Movie{}
+	updated := []*entity_movies.Movie{}
+	isNew := map[*entity_movies.Movie]bool{}
+	isUpdated := map[*entity_movies.Movie]bool{}
+	for _, movie := range batch {
+		var tmdbMatch, imdbMatch *entity_movies.Movie
+		if movie.TMDBID != nil {
+			tmdbMatch = byTMDB[*movie.TMDBID]
+		}
+		if movie.IMDbID != nil {
+			imdbMatch = byIMDb[*movie.IMDbID]
+		}
+
+		// A record whose IDs belong to two different movies can't tell
+		// which one it describes, so it is skipped instead of merged
+		if conflictingIDs(movie, tmdbMatch, imdbMatch) {
+			result.Skipped++
+			continue
+		}
+		target := tmdbMatch
+		if target == nil {
+			target = imdbMatch
+		}
+
+		switch {
+		case target == nil:
+			target = movie
+			created = append(created, movie)
+			isNew[movie] = true
+		case isNew[target]:
+			mergeMovie(target, movie)
+		default:
+			mergeMovie(target, movie)
+			if !isUpdated[target] {
+				updated = append(updated, target)
+				isUpdated[target] = true
+			}
+		}
+		index(target)
+	}
+
+	if err := u.repo.SaveBatch(created, updated); err != nil {
+		return fmt.Errorf("could not save movies: %v", err)
+	}
+	result.Created += len(created)
+	result.Updated += len(updated)
+	return nil
+}
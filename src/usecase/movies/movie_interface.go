@@ -0,0 +1,22 @@
+package usecase_movies
+
+import (
+	entity_movies "app/entity/movies"
+
+	"github.com/google/uuid"
+)
+
+type IRepositoryMovie interface {
+	FindById(id uuid.UUID) (*entity_movies.Movie, error)
+	FindAllByExternalIDs(tmdbIDs []int, imdbIDs []string) ([]*entity_movies.Movie, error)
+	Search(filter entity_movies.MovieFilter, fuzzy bool) ([]*entity_movies.Movie, int64, error)
+	// FindAllByGenres returns movies with any of the genres, those matching
+	// more of them first.
+	FindAllByGenres(genres []string, excludeIDs []uuid.UUID, limit int) ([]*entity_movies.Movie, error)
+	SaveBatch(created []*entity_movies.Movie, updated []*entity_movies.Movie) error
+}
+
+type IUseCaseMovie interface {
+	GetById(id uuid.UUID) (*entity_movies.Movie, error)
+	Search(filter entity_movies.MovieFilter) (*entity_movies.MoviePage, error)
+}
@@ -0,0 +1,76 @@
+package usecase_movies
+
+import (
+	entity_movies "app/entity/movies"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultMoviePageSize = 20
+	MaxMoviePageSize     = 100
+)
+
+var ErrMovieNotFound = errors.New("movie not found")
+
+type movieUseCase struct {
+	repo IRepositoryMovie
+}
+
+func NewMovieUseCase(repo IRepositoryMovie) IUseCaseMovie {
+	return &movieUseCase{repo: repo}
+}
+
+func (u *movieUseCase) GetById(id uuid.UUID) (*entity_movies.Movie, error) {
+	movie, err := u.repo.FindById(id)
+	if err != nil {
+		return nil, ErrMovieNotFound
+	}
+	return movie, nil
+}
+
+// Search runs a full-text search and, when it finds nothing, retries by
+// title similarity to tolerate typos.
+func (u *movieUseCase) Search(filter entity_movies.MovieFilter) (*entity_movies.MoviePage, error) {
+	filter.Query = strings.TrimSpace(filter.Query)
+	filter.Genre = strings.TrimSpace(filter.Genre)
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = DefaultMoviePageSize
+	}
+	if filter.PageSize > MaxMoviePageSize {
+		return nil, fmt.Errorf("page_size must be at most %d", MaxMoviePageSize)
+	}
+	if filter.RuntimeMin != nil && filter.RuntimeMax != nil && *filter.RuntimeMin > *filter.RuntimeMax {
+		return nil, fmt.Errorf("runtime_min must not be greater than runtime_max")
+	}
+	if filter.YearFrom != nil && filter.YearTo != nil && *filter.YearFrom > *filter.YearTo {
+		return nil, fmt.Errorf("year_from must not be greater than year_to")
+	}
+
+	movies, total, err := u.repo.Search(filter, false)
+	if err != nil {
+		return nil, fmt.Errorf("could not search movies")
+	}
+	fuzzy := false
+	if total == 0 && filter.Query != "" {
+		fuzzy = true
+		movies, total, err = u.repo.Search(filter, true)
+		if err != nil {
+			return nil, fmt.Errorf("could not search movies")
+		}
+	}
+
+	return &entity_movies.MoviePage{
+		Movies:   movies,
+		Total:    total,
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Fuzzy:    fuzzy,
+	}, nil
+}
@@ -0,0 +1,37 @@
+package usecase_movies
+
+import (
+	entity_crew "app/entity/crew"
+	entity_movies "app/entity/movies"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IRepositoryWatchEntry interface {
+	Create(entry *entity_movies.WatchEntry) error
+	FindByIdAndUser(id uuid.UUID, userID int) (*entity_movies.WatchEntry, error)
+	FindByUserAndMovie(userID int, movieID uuid.UUID) (*entity_movies.WatchEntry, error)
+	FindAllByUser(userID int) ([]*entity_movies.WatchEntry, error)
+	FindAllByUsers(userIDs []int) ([]*entity_movies.WatchEntry, error)
+	// FindSimilarUsers returns the users, other than the excluded ones, who
+	// rated most of the given movies.
+	FindSimilarUsers(movieIDs []uuid.UUID, excludeUserIDs []int, limit int) ([]int, error)
+	FindAllByMovieAndUsers(movieID uuid.UUID, userIDs []int) ([]*entity_movies.WatchEntry, error)
+	Update(entry *entity_movies.WatchEntry) error
+	Delete(id uuid.UUID) error
+	// FindUnrecordedAttendances returns the "going" RSVPs of ended sessions
+	// with a movie that are not in the watch history yet, with their session.
+	FindUnrecordedAttendances(userIDs []int, endedBefore time.Time) ([]*entity_crew.SessionRSVP, error)
+	// RecordAttendance saves the entry and marks the RSVP as recorded.
+	RecordAttendance(rsvp *entity_crew.SessionRSVP, entry *entity_movies.WatchEntry) error
+}
+
+type IUseCaseWatchHistory interface {
+	Add(entry *entity_movies.WatchEntry, userID int) error
+	GetAll(userID int) ([]*entity_movies.WatchEntry, error)
+	Update(entry *entity_movies.WatchEntry, userID int) error
+	Delete(id uuid.UUID, userID int) error
+	Sync(userIDs []int) error
+	GetMovieRatings(movieID uuid.UUID, userIDs []int) (*entity_movies.MovieRatings, error)
+}
@@ -0,0 +1,197 @@
+package usecase_movies
+
+import (
+	entity_movies "app/entity/movies"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const MaxReviewLength = 5000
+
+var ErrWatchEntryNotFound = errors.New("watch history entry not found")
+
+type watchHistoryUseCase struct {
+	repo      IRepositoryWatchEntry
+	repoMovie IRepositoryMovie
+}
+
+func NewWatchHistoryUseCase(repo IRepositoryWatchEntry, repoMovie IRepositoryMovie) IUseCaseWatchHistory {
+	return &watchHistoryUseCase{
+		repo:      repo,
+		repoMovie: repoMovie,
+	}
+}
+
+// validateEntry checks the rating and review of an entry and defaults
+// WatchedAt to now.
+func validateEntry(entry *entity_movies.WatchEntry) error {
+	if entry.Rating != nil {
+		rating := *entry.Rating
+		if rating < entity_movies.MinRating || rating > entity_movies.MaxRating || math.Mod(rating*2, 1) != 0 {
+			return fmt.Errorf("rating must be between %.1f and %.1f in steps of 0.5", entity_movies.MinRating, entity_movies.MaxRating)
+		}
+	}
+
+	entry.Review = strings.TrimSpace(entry.Review)
+	if len([]rune(entry.Review)) > MaxReviewLength {
+		return fmt.Errorf("review must be at most %d characters", MaxReviewLength)
+	}
+
+	now := time.Now()
+	if entry.WatchedAt == nil {
+		entry.WatchedAt = &now
+	}
+	if entry.WatchedAt.After(now) {
+		return fmt.Errorf("watched_at can not be in the future")
+	}
+	return nil
+}
+
+func (u *watchHistoryUseCase) Add(entry *entity_movies.WatchEntry, userID int) error {
+	if entry.MovieID == nil {
+		return fmt.Errorf("movie_id is required")
+	}
+	if err := validateEntry(entry); err != nil {
+		return err
+	}
+
+	movie, err := u.repoMovie.FindById(*entry.MovieID)
+	if err != nil {
+		return ErrMovieNotFound
+	}
+	if _, err := u.repo.FindByUserAndMovie(userID, *entry.MovieID); err == nil {
+		return fmt.Errorf("movie is already in the watch history")
+	}
+
+	entry.UserID = userID
+	entry.SessionID = nil
+	if err := u.repo.Create(entry); err != nil {
+		return fmt.Errorf("could not add movie to the watch history")
+	}
+	entry.Movie = movie
+	return nil
+}
+
+func (u *watchHistoryUseCase) GetAll(userID int) ([]*entity_movies.WatchEntry, error) {
+	if err := u.Sync([]int{userID}); err != nil {
+		return nil, err
+	}
+
+	entries, err := u.repo.FindAllByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("could not load watch history")
+	}
+	return entries, nil
+}
+
+// Update replaces the rating and review of an entry, and its watch date
+// when one is given.
+func (u *watchHistoryUseCase) Update(entry *entity_movies.WatchEntry, userID int) error {
+	existing, err := u.repo.FindByIdAndUser(*entry.ID, userID)
+	if err != nil {
+		return ErrWatchEntryNotFound
+	}
+
+	if entry.WatchedAt == nil {
+		entry.WatchedAt = existing.WatchedAt
+	}
+	if err := validateEntry(entry); err != nil {
+		return err
+	}
+
+	existing.WatchedAt = entry.WatchedAt
+	existing.Rating = entry.Rating
+	existing.Review = entry.Review
+	if err := u.repo.Update(existing); err != nil {
+		return fmt.Errorf("could not update watch history entry")
+	}
+	*entry = *existing
+	return nil
+}
+
+// Delete removes an entry. Sessions already recorded are not added back.
+func (u *watchHistoryUseCase) Delete(id uuid.UUID, userID int) error {
+	if _, err := u.repo.FindByIdAndUser(id, userID); err != nil {
+		return ErrWatchEntryNotFound
+	}
+	if err := u.repo.Delete(id); err != nil {
+		return fmt.Errorf("could not delete watch history entry")
+	}
+	return nil
+}
+
+// Sync adds to the watch history of the users the movies of the sessions
+// they said they were going to and that have ended. Each attendance is
+// recorded once, so deleted entries stay deleted.
+func (u *watchHistoryUseCase) Sync(userIDs []int) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	rsvps, err := u.repo.FindUnrecordedAttendances(userIDs, time.Now())
+	if err != nil {
+		return fmt.Errorf("could not load attended sessions")
+	}
+	for _, rsvp := range rsvps {
+		session := rsvp.Session
+		entry, err := u.repo.FindByUserAndMovie(rsvp.UserID, *session.MovieID)
+		if err != nil {
+			entry = &entity_movies.WatchEntry{
+				UserID:  rsvp.UserID,
+				MovieID: session.MovieID,
+			}
+		}
+		if entry.WatchedAt == nil || session.EndAt.After(*entry.WatchedAt) {
+			entry.SessionID = session.ID
+			entry.WatchedAt = session.EndAt
+		}
+
+		if err := u.repo.RecordAttendance(rsvp, entry); err != nil {
+			return fmt.Errorf("could not update watch history")
+		}
+	}
+	return nil
+}
+
+// GetMovieRatings aggregates the entries of the given users for a movie.
+// The average is rounded to two decimals.
+func (u *watchHistoryUseCase) GetMovieRatings(movieID uuid.UUID, userIDs []int) (*entity_movies.MovieRatings, error) {
+	movie, err := u.repoMovie.FindById(movieID)
+	if err != nil {
+		return nil, ErrMovieNotFound
+	}
+	if err := u.Sync(userIDs); err != nil {
+		return nil, err
+	}
+
+	entries := []*entity_movies.WatchEntry{}
+	if len(userIDs) > 0 {
+		entries, err = u.repo.FindAllByMovieAndUsers(movieID, userIDs)
+		if err != nil {
+			return nil, fmt.Errorf("could not load ratings")
+		}
+	}
+
+	ratings := &entity_movies.MovieRatings{
+		Movie:   movie,
+		Watched: len(entries),
+		Entries: entries,
+	}
+	sum := 0.0
+	for _, entry := range entries {
+		if entry.Rating != nil {
+			sum += *entry.Rating
+			ratings.Ratings++
+		}
+	}
+	if ratings.Ratings > 0 {
+		average := math.Round(sum/float64(ratings.Ratings)*100) / 100
+		ratings.Average = &average
+	}
+	return ratings, nil
+}
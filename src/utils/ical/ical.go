@@ -0,0 +1,175 @@
+package ical
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+// Event is a VEVENT. Times are written in UTC, or as local times with TZID
+// when TimeZone is set, so recurrence rules expand in that zone and keep
+// their wall-clock time across DST changes. The calendar then describes the
+// zone in a VTIMEZONE.
+type Event struct {
+	UID          string
+	Stamp        time.Time // Last modification, used as DTSTAMP and LAST-MODIFIED
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Location     string
+	URL          string
+	Status       string
+	RRule        string // e.g. FREQ=WEEKLY;COUNT=10, without the RRULE: prefix
+	ExDates      []time.Time
+	RecurrenceID *time.Time // Set on events that replace one occurrence of the series with the same UID
+	TimeZone     string     // IANA time zone name, like America/Sao_Paulo
+}
+
+// Calendar is a VCALENDAR published as an iCalendar (RFC 5545) feed.
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []*Event
+}
+
+// Encode renders the calendar with CRLF line endings and lines folded at
+// 75 octets.
+func (c *Calendar) Encode() []byte {
+	var buf bytes.Buffer
+	write := func(name string, value string) {
+		writeLine(&buf, name+":"+value)
+	}
+
+	write("BEGIN", "VCALENDAR")
+	write("VERSION", "2.0")
+	write("PRODID", c.ProdID)
+	write("CALSCALE", "GREGORIAN")
+	write("METHOD", "PUBLISH")
+	if c.Name != "" {
+		write("X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	// Every TZID needs a VTIMEZONE covering the times written in it
+	zones := []string{}
+	ranges := map[string][2]time.Time{}
+	for _, event := range c.Events {
+		loc := event.location()
+		if loc == nil {
+			continue
+		}
+		times := append([]time.Time{event.Start, event.End}, event.ExDates...)
+		if event.RecurrenceID != nil {
+			times = append(times, *event.RecurrenceID)
+		}
+		zone, ok := ranges[loc.String()]
+		if !ok {
+			zones = append(zones, loc.String())
+			zone = [2]time.Time{event.Start, event.Start}
+		}
+		for _, t := range times {
+			if t.Before(zone[0]) {
+				zone[0] = t
+			}
+			if t.After(zone[1]) {
+				zone[1] = t
+			}
+		}
+		ranges[loc.String()] = zone
+	}
+	sort.Strings(zones)
+	for _, name := range zones {
+		loc, _ := time.LoadLocation(name)
+		writeTimeZone(write, loc, ranges[name][0], ranges[name][1])
+	}
+
+	for _, event := range c.Events {
+		writeTime := func(name string, t time.Time) {
+			write(name, formatTime(t))
+		}
+		if loc := event.location(); loc != nil {
+			writeTime = func(name string, t time.Time) {
+				write(name+";TZID="+loc.String(), t.In(loc).Format(localDateTimeFormat))
+			}
+		}
+
+		write("BEGIN", "VEVENT")
+		write("UID", escapeText(event.UID))
+		write("DTSTAMP", formatTime(event.Stamp))
+		write("LAST-MODIFIED", formatTime(event.Stamp))
+		writeTime("DTSTART", event.Start)
+		writeTime("DTEND", event.End)
+		if event.RRule != "" {
+			write("RRULE", event.RRule)
+		}
+		for _, exDate := range event.ExDates {
+			writeTime("EXDATE", exDate)
+		}
+		if event.RecurrenceID != nil {
+			writeTime("RECURRENCE-ID", *event.RecurrenceID)
+		}
+		write("SUMMARY", escapeText(event.Summary))
+		if event.Description != "" {
+			write("DESCRIPTION", escapeText(event.Description))
+		}
+		if event.Location != "" {
+			write("LOCATION", escapeText(event.Location))
+		}
+		if event.URL != "" {
+			write("URL", event.URL)
+		}
+		if event.Status != "" {
+			write("STATUS", event.Status)
+		}
+		write("END", "VEVENT")
+	}
+	write("END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+// location returns the time zone the event is written in, or nil when it
+// is written in UTC.
+func (e *Event) location() *time.Location {
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil || loc == time.UTC {
+		return nil
+	}
+	return loc
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// writeLine folds a content line, never splitting a UTF-8 sequence.
+func writeLine(buf *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // The leading space of a continuation counts
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,76 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeTimeZones(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	start := time.Date(2026, 3, 9, 9, 0, 0, 0, berlin)
+
+	tests := []struct {
+		name     string
+		timeZone string
+		want     []string
+		wantNot  []string
+	}{
+		{
+			name:     "zone with daylight saving time",
+			timeZone: "Europe/Berlin",
+			want: []string{
+				"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\n" +
+					"BEGIN:STANDARD\r\nDTSTART:20260101T000000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nEND:STANDARD\r\n" +
+					"BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU\r\nEND:DAYLIGHT\r\n" +
+					"BEGIN:STANDARD\r\nDTSTART:20261025T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nTZNAME:CET\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU\r\nEND:STANDARD\r\n" +
+					"END:VTIMEZONE\r\n",
+				"DTSTART;TZID=Europe/Berlin:20260309T090000\r\n",
+				"EXDATE;TZID=Europe/Berlin:20260330T090000\r\n",
+			},
+		},
+		{
+			name:     "zone without daylight saving time",
+			timeZone: "America/Sao_Paulo",
+			want: []string{
+				"BEGIN:VTIMEZONE\r\nTZID:America/Sao_Paulo\r\n" +
+					"BEGIN:STANDARD\r\nDTSTART:20260101T000000\r\nTZOFFSETFROM:-0300\r\nTZOFFSETTO:-0300\r\nTZNAME:-03\r\nEND:STANDARD\r\n" +
+					"END:VTIMEZONE\r\n",
+				"DTSTART;TZID=America/Sao_Paulo:20260309T050000\r\n",
+			},
+		},
+		{
+			name:     "utc",
+			timeZone: "UTC",
+			want:     []string{"DTSTART:20260309T080000Z\r\n", "EXDATE:20260330T070000Z\r\n"},
+			wantNot:  []string{"VTIMEZONE", "TZID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := &Calendar{ProdID: "-//Test//EN", Events: []*Event{{
+				UID:      "series@test",
+				Start:    start,
+				End:      start.Add(time.Hour),
+				RRule:    "FREQ=WEEKLY",
+				ExDates:  []time.Time{start.AddDate(0, 0, 21)},
+				TimeZone: tt.timeZone,
+			}}}
+			got := string(calendar.Encode())
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("Encode() = %q, want it to contain %q", got, want)
+				}
+			}
+			for _, wantNot := range tt.wantNot {
+				if strings.Contains(got, wantNot) {
+					t.Errorf("Encode() = %q, want no %q", got, wantNot)
+				}
+			}
+		})
+	}
+}
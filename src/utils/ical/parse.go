@@ -0,0 +1,299 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	localDateTimeFormat = "20060102T150405"
+	dateFormat          = "20060102"
+)
+
+// ParsedEvent is a VEVENT read from a calendar. Err is set when the event
+// could not be understood; the other fields are then partial.
+type ParsedEvent struct {
+	Index        int // 1-based position in the file
+	UID          string
+	Summary      string
+	Status       string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID *time.Time // Set on events that replace one occurrence of a series
+	Err          error
+}
+
+// property is a content line: NAME;PARAM=VALUE:value
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the VEVENTs of an iCalendar stream. Floating times, which
+// carry no time zone, are read in defaultLoc. Errors in one event are
+// reported on the event; the error returned means the file is not a
+// calendar at all.
+func Parse(r io.Reader, defaultLoc *time.Location) ([]*ParsedEvent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*ParsedEvent{}
+	var current *ParsedEvent
+	var properties []*property
+	calendar := false
+	depth := 0 // Components nested in the current event, like VALARM
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := parseLine(line)
+		if err != nil {
+			if current != nil && current.Err == nil {
+				current.Err = err
+			}
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			calendar = true
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT") && current == nil:
+			current = &ParsedEvent{Index: len(events) + 1}
+			properties = nil
+		case prop.name == "BEGIN" && current != nil:
+			depth++
+		case prop.name == "END" && current != nil && depth > 0:
+			depth--
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && current != nil:
+			if current.Err == nil {
+				current.Err = current.load(properties, defaultLoc)
+			}
+			events = append(events, current)
+			current = nil
+		case current != nil && depth == 0:
+			properties = append(properties, prop)
+		}
+	}
+
+	if !calendar {
+		return nil, fmt.Errorf("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+	return events, nil
+}
+
+// unfold joins the continuation lines, which start with a space or a tab.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lines := []string{}
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+func parseLine(line string) (*property, error) {
+	// The value starts at the first colon outside a quoted parameter value
+	quoted := false
+	colon := -1
+	for i, char := range line {
+		if char == '"' {
+			quoted = !quoted
+		} else if char == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon == -1 {
+		return nil, fmt.Errorf("malformed line %q", line)
+	}
+
+	parts := splitUnquoted(line[:colon], ';')
+	prop := &property{name: strings.ToUpper(parts[0]), params: map[string]string{}, value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		name, value, found := strings.Cut(param, "=")
+		if !found {
+			return nil, fmt.Errorf("malformed parameter %q", param)
+		}
+		prop.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+func splitUnquoted(value string, separator rune) []string {
+	parts := []string{}
+	quoted := false
+	start := 0
+	for i, char := range value {
+		if char == '"' {
+			quoted = !quoted
+		} else if char == separator && !quoted {
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+func unescapeText(value string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+	return replacer.Replace(value)
+}
+
+func (e *ParsedEvent) load(properties []*property, defaultLoc *time.Location) error {
+	var end *time.Time
+	var duration *time.Duration
+	hasStart := false
+	for _, prop := range properties {
+		switch prop.name {
+		case "UID":
+			e.UID = strings.TrimSpace(prop.value)
+		case "SUMMARY":
+			e.Summary = unescapeText(prop.value)
+		case "STATUS":
+			e.Status = strings.ToUpper(strings.TrimSpace(prop.value))
+		case "DTSTART":
+			start, allDay, err := parseTime(prop, defaultLoc)
+			if err != nil {
+				return fmt.Errorf("invalid DTSTART: %w", err)
+			}
+			e.Start, e.AllDay, hasStart = start, allDay, true
+		case "DTEND":
+			value, _, err := parseTime(prop, defaultLoc)
+			if err != nil {
+				return fmt.Errorf("invalid DTEND: %w", err)
+			}
+			end = &value
+		case "DURATION":
+			value, err := parseDuration(prop.value)
+			if err != nil {
+				return fmt.Errorf("invalid DURATION: %w", err)
+			}
+			duration = &value
+		case "RRULE":
+			e.RRule = strings.TrimSpace(prop.value)
+		case "EXDATE":
+			for _, value := range strings.Split(prop.value, ",") {
+				exDate, _, err := parseTime(&property{params: prop.params, value: value}, defaultLoc)
+				if err != nil {
+					return fmt.Errorf("invalid EXDATE: %w", err)
+				}
+				e.ExDates = append(e.ExDates, exDate)
+			}
+		case "RECURRENCE-ID":
+			value, _, err := parseTime(prop, defaultLoc)
+			if err != nil {
+				return fmt.Errorf("invalid RECURRENCE-ID: %w", err)
+			}
+			e.RecurrenceID = &value
+		}
+	}
+
+	if e.UID == "" {
+		return fmt.Errorf("missing UID")
+	}
+	if !hasStart {
+		return fmt.Errorf("missing DTSTART")
+	}
+	switch {
+	case end != nil:
+		e.End = *end
+	case duration != nil:
+		e.End = e.Start.Add(*duration)
+	case e.AllDay:
+		e.End = e.Start.AddDate(0, 0, 1)
+	default:
+		return fmt.Errorf("missing DTEND or DURATION")
+	}
+	if !e.End.After(e.Start) {
+		return fmt.Errorf("the event ends before it starts")
+	}
+	return nil
+}
+
+// loadLocation resolves a TZID, which names an IANA zone or, in files from
+// Outlook and Exchange, a Windows zone.
+func loadLocation(tzid string) (*time.Location, error) {
+	name := strings.TrimSpace(strings.TrimPrefix(tzid, "/"))
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc, nil
+	}
+	if iana, ok := windowsZones[name]; ok {
+		if loc, err := time.LoadLocation(iana); err == nil {
+			return loc, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown time zone %q", tzid)
+}
+
+// parseTime reads a DATE or DATE-TIME value. UTC times end in Z, zoned
+// times name their zone in TZID and floating times have neither.
+func parseTime(prop *property, defaultLoc *time.Location) (time.Time, bool, error) {
+	loc := defaultLoc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		zone, err := loadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		loc = zone
+	}
+
+	value := strings.TrimSpace(prop.value)
+	switch {
+	case prop.params["VALUE"] == "DATE" || len(value) == len(dateFormat):
+		t, err := time.ParseInLocation(dateFormat, value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse(dateTimeFormat, value)
+		return t, false, err
+	default:
+		t, err := time.ParseInLocation(localDateTimeFormat, value, loc)
+		return t, false, err
+	}
+}
+
+var durationPattern = regexp.MustCompile(`^([+-])?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration reads a DURATION value like P1DT2H or -PT15M.
+func parseDuration(value string) (time.Duration, error) {
+	match := durationPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil || value == "P" || strings.HasSuffix(value, "T") {
+		return 0, fmt.Errorf("malformed duration %q", value)
+	}
+
+	units := []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second}
+	duration := time.Duration(0)
+	for i, unit := range units {
+		if match[i+2] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(match[i+2])
+		if err != nil {
+			return 0, fmt.Errorf("malformed duration %q", value)
+		}
+		duration += time.Duration(n) * unit
+	}
+	if match[1] == "-" {
+		duration = -duration
+	}
+	return duration, nil
+}
@@ -0,0 +1,276 @@
+package ical
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "PT15M", want: 15 * time.Minute},
+		{value: "P1DT2H", want: 26 * time.Hour},
+		{value: "P2W", want: 14 * 24 * time.Hour},
+		{value: "PT1H30M45S", want: time.Hour + 30*time.Minute + 45*time.Second},
+		{value: "+P1D", want: 24 * time.Hour},
+		{value: "-PT15M", want: -15 * time.Minute},
+		{value: "P", wantErr: true},
+		{value: "PT", wantErr: true},
+		{value: "P1DT", wantErr: true},
+		{value: "1H", wantErr: true},
+		{value: "P1H", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDuration(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseDuration(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDuration(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseDuration(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:utc@example.com",
+		"SUMMARY:Dentist\\, then lunch",
+		"DTSTART:20260310T130000Z",
+		"DTEND:20260310T150000Z",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:series@exam",
+		" ple.com",
+		"DTSTART;TZID=America/New_York:20260305T090000",
+		"DURATION:PT8H",
+		"RRULE:FREQ=WEEKLY;BYDAY=TH",
+		"EXDATE;TZID=America/New_York:20260312T090000,20260319T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:series@example.com",
+		"RECURRENCE-ID;TZID=America/New_York:20260326T090000",
+		"DTSTART:20260326T100000",
+		"DTEND:20260326T120000",
+		"STATUS:confirmed",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:holiday@example.com",
+		"DTSTART;VALUE=DATE:20260421",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20260501T100000Z",
+		"DTEND:20260501T110000Z",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:backwards@example.com",
+		"DTSTART:20260501T100000Z",
+		"DTEND:20260501T090000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := Parse(strings.NewReader(calendar), saoPaulo)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 6 {
+		t.Fatalf("Parse() returned %d events, want 6", len(events))
+	}
+
+	recurrenceID := time.Date(2026, 3, 26, 9, 0, 0, 0, newYork)
+	tests := []struct {
+		name    string
+		event   *ParsedEvent
+		want    *ParsedEvent
+		wantErr bool
+	}{
+		{
+			name:  "utc times and escaped text",
+			event: events[0],
+			want: &ParsedEvent{
+				Index:   1,
+				UID:     "utc@example.com",
+				Summary: "Dentist, then lunch",
+				Start:   time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC),
+				End:     time.Date(2026, 3, 10, 15, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "folded uid, zoned series with duration and exdates",
+			event: events[1],
+			want: &ParsedEvent{
+				Index: 2,
+				UID:   "series@example.com",
+				Start: time.Date(2026, 3, 5, 9, 0, 0, 0, newYork),
+				End:   time.Date(2026, 3, 5, 17, 0, 0, 0, newYork),
+				RRule: "FREQ=WEEKLY;BYDAY=TH",
+				ExDates: []time.Time{
+					time.Date(2026, 3, 12, 9, 0, 0, 0, newYork),
+					time.Date(2026, 3, 19, 9, 0, 0, 0, newYork),
+				},
+			},
+		},
+		{
+			name:  "floating override of one occurrence",
+			event: events[2],
+			want: &ParsedEvent{
+				Index:        3,
+				UID:          "series@example.com",
+				Status:       "CONFIRMED",
+				Start:        time.Date(2026, 3, 26, 10, 0, 0, 0, saoPaulo),
+				End:          time.Date(2026, 3, 26, 12, 0, 0, 0, saoPaulo),
+				RecurrenceID: &recurrenceID,
+			},
+		},
+		{
+			name:  "all day event without end",
+			event: events[3],
+			want: &ParsedEvent{
+				Index:  4,
+				UID:    "holiday@example.com",
+				Start:  time.Date(2026, 4, 21, 0, 0, 0, 0, saoPaulo),
+				End:    time.Date(2026, 4, 22, 0, 0, 0, 0, saoPaulo),
+				AllDay: true,
+			},
+		},
+		{name: "missing uid", event: events[4], wantErr: true},
+		{name: "ends before it starts", event: events[5], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantErr {
+				if tt.event.Err == nil {
+					t.Errorf("event %d has no error", tt.event.Index)
+				}
+				return
+			}
+			if tt.event.Err != nil {
+				t.Fatalf("event %d error = %v", tt.event.Index, tt.event.Err)
+			}
+			if !reflect.DeepEqual(tt.event, tt.want) {
+				t.Errorf("event = %+v, want %+v", tt.event, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNotACalendar(t *testing.T) {
+	if _, err := Parse(strings.NewReader("BEGIN:VEVENT\r\nEND:VEVENT\r\n"), time.UTC); err == nil {
+		t.Error("Parse() of a file without VCALENDAR succeeded, want an error")
+	}
+}
+
+func TestParseOutlook(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	// As exported by Outlook: Windows zone names in TZID, described by a
+	// VTIMEZONE that is not read
+	calendar := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//Microsoft Corporation//Outlook 16.0 MIMEDIR//EN",
+		"VERSION:2.0",
+		"METHOD:PUBLISH",
+		"X-MS-OLK-FORCEINSPECTOROPEN:TRUE",
+		"BEGIN:VTIMEZONE",
+		"TZID:W. Europe Standard Time",
+		"BEGIN:STANDARD",
+		"DTSTART:16011028T030000",
+		"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"END:STANDARD",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:16010325T020000",
+		"RRULE:FREQ=YEARLY;BYDAY=-1SU;BYMONTH=3",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"END:DAYLIGHT",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"CLASS:PUBLIC",
+		"CREATED:20260301T101500Z",
+		"DTEND;TZID=\"W. Europe Standard Time\":20260323T170000",
+		"DTSTAMP:20260301T101500Z",
+		"DTSTART;TZID=\"W. Europe Standard Time\":20260323T090000",
+		"EXDATE;TZID=W. Europe Standard Time:20260330T090000",
+		"RRULE:FREQ=WEEKLY;COUNT=4;BYDAY=MO",
+		"SUMMARY;LANGUAGE=en-us:Off",
+		"TRANSP:OPAQUE",
+		"UID:040000008200E00074C5B7101A82E00800000000",
+		"X-MICROSOFT-CDO-BUSYSTATUS:OOF",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;TZID=Customized Time Zone:20260323T090000",
+		"DTEND;TZID=Customized Time Zone:20260323T170000",
+		"UID:custom@example.com",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := Parse(strings.NewReader(calendar), time.UTC)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Parse() returned %d events, want 2", len(events))
+	}
+	want := &ParsedEvent{
+		Index:   1,
+		UID:     "040000008200E00074C5B7101A82E00800000000",
+		Summary: "Off",
+		Start:   time.Date(2026, 3, 23, 9, 0, 0, 0, berlin),
+		End:     time.Date(2026, 3, 23, 17, 0, 0, 0, berlin),
+		RRule:   "FREQ=WEEKLY;COUNT=4;BYDAY=MO",
+		ExDates: []time.Time{time.Date(2026, 3, 30, 9, 0, 0, 0, berlin)},
+	}
+	if !reflect.DeepEqual(events[0], want) {
+		t.Errorf("event = %+v, want %+v", events[0], want)
+	}
+	if events[1].Err == nil {
+		t.Errorf("event with an unknown zone has no error")
+	}
+}
+
+func TestWindowsZones(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Berlin"); err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	for windows, iana := range windowsZones {
+		if _, err := loadLocation(windows); err != nil {
+			t.Errorf("%q maps to %q: %v", windows, iana, err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// transition is a change of the UTC offset of a time zone.
+type transition struct {
+	at         time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	dst        bool
+}
+
+// transitions returns the changes of offset of loc in [from, to). The time
+// zone database is not exposed, so days are scanned and the change is then
+// found to the second.
+func transitions(loc *time.Location, from, to time.Time) []transition {
+	found := []transition{}
+	_, offset := from.In(loc).Zone()
+	for day := from; day.Before(to); day = day.Add(24 * time.Hour) {
+		next := day.Add(24 * time.Hour)
+		_, nextOffset := next.In(loc).Zone()
+		if nextOffset == offset {
+			continue
+		}
+		low, high := day.Unix(), next.Unix()
+		for high-low > 1 {
+			middle := low + (high-low)/2
+			if _, middleOffset := time.Unix(middle, 0).In(loc).Zone(); middleOffset == offset {
+				low = middle
+			} else {
+				high = middle
+			}
+		}
+		at := time.Unix(high, 0).In(loc)
+		name, _ := at.Zone()
+		found = append(found, transition{at: at, offsetFrom: offset, offsetTo: nextOffset, name: name, dst: at.IsDST()})
+		offset = nextOffset
+	}
+	return found
+}
+
+// yearlyRule returns the RRULE of a transition that happens every year on
+// the same weekday of its month, like the last Sunday of March, and false
+// when later transitions do not follow it.
+func yearlyRule(loc *time.Location, change transition) (string, bool) {
+	local := change.at.Add(-time.Second).In(loc)
+	year, month, day := local.Date()
+	week := (day-1)/7 + 1
+	if day+7 > daysIn(year, month) {
+		week = -1
+	}
+	weekday := local.Weekday()
+
+	// The next years must change on the day the rule gives
+	for next := year + 1; next <= year+2; next++ {
+		changes := transitions(loc, time.Date(next, month, 1, 0, 0, 0, 0, time.UTC).Add(-24*time.Hour), time.Date(next, month+1, 1, 0, 0, 0, 0, time.UTC).Add(24*time.Hour))
+		if len(changes) != 1 || changes[0].offsetTo != change.offsetTo || changes[0].at.Add(-time.Second).In(loc).Day() != weekdayOf(next, month, week, weekday) {
+			return "", false
+		}
+	}
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%d%s", month, week, weekdayCodes[weekday]), true
+}
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// weekdayOf returns the day of the week-th weekday of a month, counting
+// from its end when week is negative.
+func weekdayOf(year int, month time.Month, week int, weekday time.Weekday) int {
+	if week < 0 {
+		last := daysIn(year, month)
+		shift := (int(time.Date(year, month, last, 0, 0, 0, 0, time.UTC).Weekday()) - int(weekday) + 7) % 7
+		return last - shift
+	}
+	first := (int(weekday) - int(time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()) + 7) % 7
+	return 1 + first + (week-1)*7
+}
+
+// writeTimeZone writes the VTIMEZONE of loc for times from from to to, as
+// RFC 5545 requires for every TZID. The offsets of the years in between are
+// listed, and the changes of the last year repeat yearly when the zone
+// keeps them, so series that never end are described too.
+func writeTimeZone(write func(name string, value string), loc *time.Location, from, to time.Time) {
+	start := time.Date(from.In(loc).Year(), time.January, 1, 0, 0, 0, 0, loc)
+	lastYear := to.In(loc).Year()
+	end := time.Date(lastYear+1, time.January, 1, 0, 0, 0, 0, loc)
+
+	write("BEGIN", "VTIMEZONE")
+	write("TZID", loc.String())
+	name, offset := start.Zone()
+	writeObservance(write, transition{at: start, offsetFrom: offset, offsetTo: offset, name: name, dst: start.IsDST()}, "")
+	for _, change := range transitions(loc, start, end) {
+		rule := ""
+		if change.at.Year() == lastYear {
+			rule, _ = yearlyRule(loc, change)
+		}
+		writeObservance(write, change, rule)
+	}
+	write("END", "VTIMEZONE")
+}
+
+// writeObservance writes a STANDARD or DAYLIGHT component. Its DTSTART is
+// the local time before the change.
+func writeObservance(write func(name string, value string), change transition, rule string) {
+	component := "STANDARD"
+	if change.dst {
+		component = "DAYLIGHT"
+	}
+	write("BEGIN", component)
+	write("DTSTART", change.at.In(time.FixedZone("", change.offsetFrom)).Format(localDateTimeFormat))
+	write("TZOFFSETFROM", formatOffset(change.offsetFrom))
+	write("TZOFFSETTO", formatOffset(change.offsetTo))
+	if change.name != "" {
+		write("TZNAME", escapeText(change.name))
+	}
+	if rule != "" {
+		write("RRULE", rule)
+	}
+	write("END", component)
+}
+
+// formatOffset writes a UTC offset in seconds as +HHMM, or +HHMMSS when it
+// has seconds.
+func formatOffset(offset int) string {
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	value := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset/60%60)
+	if offset%60 != 0 {
+		value += fmt.Sprintf("%02d", offset%60)
+	}
+	return value
+}
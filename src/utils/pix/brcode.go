@@ -0,0 +1,131 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	pixGUI            = "br.gov.bcb.pix"
+	maxMerchantName   = 25
+	maxMerchantCity   = 15
+	maxTxID           = 25
+	maxPayloadLength  = 512
+	defaultTxID       = "***"
+	currencyBRL       = "986"
+	countryBR         = "BR"
+	categoryUndefined = "0000"
+)
+
+// Payload holds the data of a static Pix charge.
+type Payload struct {
+	Key          string
+	MerchantName string
+	MerchantCity string
+	AmountCents  int64  // 0 lets the payer type the amount
+	TxID         string // Up to 25 letters and digits, "***" when empty
+	Description  string
+}
+
+// field encodes one EMV TLV field: two digit ID, two digit length, value.
+func field(id string, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// sanitize removes accents and anything but letters, digits and spaces and
+// truncates the result to size bytes.
+func sanitize(value string, size int) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(value) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' '):
+			b.WriteRune(r)
+		}
+	}
+	result := strings.Join(strings.Fields(b.String()), " ")
+	if len(result) > size {
+		result = strings.TrimSpace(result[:size])
+	}
+	return result
+}
+
+// CRC16 computes the CRC-16/CCITT-FALSE checksum (polynomial 0x1021, initial
+// value 0xFFFF) required by the BR Code specification.
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// BuildPayload returns the EMV "copia e cola" string of a static Pix BR Code.
+func BuildPayload(p Payload) (string, error) {
+	key := strings.TrimSpace(p.Key)
+	if key == "" {
+		return "", fmt.Errorf("pix key is required")
+	}
+	name := sanitize(p.MerchantName, maxMerchantName)
+	if name == "" {
+		return "", fmt.Errorf("merchant name is required")
+	}
+	city := sanitize(p.MerchantCity, maxMerchantCity)
+	if city == "" {
+		return "", fmt.Errorf("merchant city is required")
+	}
+	if p.AmountCents < 0 {
+		return "", fmt.Errorf("amount must not be negative")
+	}
+
+	txID := defaultTxID
+	if p.TxID != "" {
+		txID = strings.ReplaceAll(sanitize(p.TxID, maxTxID), " ", "")
+		if txID == "" {
+			txID = defaultTxID
+		}
+	}
+
+	account := field("00", pixGUI) + field("01", key)
+	if description := sanitize(p.Description, 72); description != "" {
+		account += field("02", description)
+	}
+	if len(account) > 99 {
+		return "", fmt.Errorf("pix key and description are too long")
+	}
+
+	payload := field("00", "01") +
+		field("26", account) +
+		field("52", categoryUndefined) +
+		field("53", currencyBRL)
+	if p.AmountCents > 0 {
+		payload += field("54", fmt.Sprintf("%d.%02d", p.AmountCents/100, p.AmountCents%100))
+	}
+	payload += field("58", countryBR) +
+		field("59", name) +
+		field("60", city) +
+		field("62", field("05", txID)) +
+		"6304"
+	payload += fmt.Sprintf("%04X", CRC16(payload))
+
+	if len(payload) > maxPayloadLength {
+		return "", fmt.Errorf("pix payload is too long")
+	}
+	return payload, nil
+}
+
+// QRCodePNG renders the payload as a PNG QR code of size x size pixels.
+func QRCodePNG(payload string, size int) ([]byte, error) {
+	return qrcode.Encode(payload, qrcode.Medium, size)
+}
@@ -0,0 +1,100 @@
+package pix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// bcbPayload is the static BR Code example from the BCB "Manual do BR Code".
+const bcbPayload = "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-426655440000" +
+	"5204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D"
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want uint16
+	}{
+		{name: "empty", data: "", want: 0xFFFF},
+		{name: "check value", data: "123456789", want: 0x29B1},
+		{name: "bcb payload", data: strings.TrimSuffix(bcbPayload, "1D3D"), want: 0x1D3D},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CRC16(tt.data); got != tt.want {
+				t.Errorf("CRC16(%q) = %04X, want %04X", tt.data, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload Payload
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "bcb reference",
+			payload: Payload{
+				Key:          "123e4567-e12b-12d1-a456-426655440000",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "BRASILIA",
+			},
+			want: bcbPayload,
+		},
+		{
+			name: "amount, txid and accents",
+			payload: Payload{
+				Key:          "fulano@example.com",
+				MerchantName: "João Araújo",
+				MerchantCity: "São Paulo",
+				AmountCents:  1050,
+				TxID:         "Sessao 42",
+			},
+			want: "00020126400014br.gov.bcb.pix0118fulano@example.com" +
+				"520400005303986540510.50" +
+				"5802BR5911Joao Araujo6009Sao Paulo62120508Sessao426304B34F",
+		},
+		{
+			name:    "missing key",
+			payload: Payload{MerchantName: "Fulano", MerchantCity: "BRASILIA"},
+			wantErr: true,
+		},
+		{
+			name:    "name without letters",
+			payload: Payload{Key: "fulano@example.com", MerchantName: "!!!", MerchantCity: "BRASILIA"},
+			wantErr: true,
+		},
+		{
+			name:    "negative amount",
+			payload: Payload{Key: "fulano@example.com", MerchantName: "Fulano", MerchantCity: "BRASILIA", AmountCents: -1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildPayload(tt.payload)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("BuildPayload() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildPayload() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildPayload() = %q, want %q", got, tt.want)
+			}
+			body, crc := got[:len(got)-4], got[len(got)-4:]
+			if want := fmt.Sprintf("%04X", CRC16(body)); crc != want {
+				t.Errorf("BuildPayload() checksum = %s, want %s", crc, want)
+			}
+		})
+	}
+}
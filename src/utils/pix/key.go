@@ -0,0 +1,165 @@
+package pix
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	KeyTypeCPF   = "cpf"
+	KeyTypeCNPJ  = "cnpj"
+	KeyTypeEmail = "email"
+	KeyTypePhone = "phone"
+	KeyTypeEVP   = "evp" // Random key generated by the bank
+)
+
+const maxEmailLength = 77
+
+var (
+	evpPattern   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	phonePattern = regexp.MustCompile(`^\+55[1-9][1-9](9[0-9]{8}|[2-8][0-9]{7})$`)
+	cnpjPattern  = regexp.MustCompile(`^[0-9A-Z]{12}[0-9]{2}$`)
+)
+
+func IsValidKeyType(keyType string) bool {
+	switch keyType {
+	case KeyTypeCPF, KeyTypeCNPJ, KeyTypeEmail, KeyTypePhone, KeyTypeEVP:
+		return true
+	}
+	return false
+}
+
+// DetectKeyType guesses the type of a key as typed by a user. Eleven digits
+// are read as a CPF; phones must start with "+" to be detected.
+func DetectKeyType(key string) string {
+	key = strings.TrimSpace(key)
+	switch {
+	case key == "":
+		return ""
+	case strings.Contains(key, "@"):
+		return KeyTypeEmail
+	case strings.HasPrefix(key, "+"):
+		return KeyTypePhone
+	case evpPattern.MatchString(key):
+		return KeyTypeEVP
+	}
+
+	switch document := stripDocument(key); len(document) {
+	case 11:
+		return KeyTypeCPF
+	case 14:
+		return KeyTypeCNPJ
+	}
+	return ""
+}
+
+// NormalizeKey validates a key of the given type and returns it in the
+// format the DICT stores: digits only for CPF and CNPJ, lowercase emails,
+// E.164 phones and lowercase EVPs.
+func NormalizeKey(keyType string, key string) (string, error) {
+	key = strings.TrimSpace(key)
+	switch keyType {
+	case KeyTypeCPF:
+		cpf := stripDocument(key)
+		if !isValidCPF(cpf) {
+			return "", fmt.Errorf("invalid CPF")
+		}
+		return cpf, nil
+	case KeyTypeCNPJ:
+		cnpj := strings.ToUpper(stripDocument(key))
+		if !isValidCNPJ(cnpj) {
+			return "", fmt.Errorf("invalid CNPJ")
+		}
+		return cnpj, nil
+	case KeyTypeEmail:
+		address, err := mail.ParseAddress(key)
+		if err != nil || address.Address != key || address.Name != "" {
+			return "", fmt.Errorf("invalid email")
+		}
+		if len(key) > maxEmailLength {
+			return "", fmt.Errorf("email must have at most %d characters", maxEmailLength)
+		}
+		return strings.ToLower(key), nil
+	case KeyTypePhone:
+		phone := normalizePhone(key)
+		if !phonePattern.MatchString(phone) {
+			return "", fmt.Errorf("phone must be a brazilian number in the +55DDNNNNNNNNN format")
+		}
+		return phone, nil
+	case KeyTypeEVP:
+		if !evpPattern.MatchString(key) {
+			return "", fmt.Errorf("random key must be a UUID")
+		}
+		return uuid.MustParse(key).String(), nil
+	}
+	return "", fmt.Errorf("unknown key type")
+}
+
+// stripDocument removes the punctuation of formatted CPFs and CNPJs.
+func stripDocument(value string) string {
+	return strings.NewReplacer(".", "", "-", "", "/", "", " ", "").Replace(value)
+}
+
+func normalizePhone(value string) string {
+	phone := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(value)
+	if strings.HasPrefix(phone, "+") {
+		return phone
+	}
+	// Local numbers with area code, with or without the country code
+	if len(phone) == 10 || len(phone) == 11 {
+		return "+55" + phone
+	}
+	return "+" + phone
+}
+
+func isValidCPF(cpf string) bool {
+	if len(cpf) != 11 || strings.Count(cpf, cpf[:1]) == 11 {
+		return false
+	}
+	for _, r := range cpf {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return checkDigit(cpf[:9], 10) == cpf[9] && checkDigit(cpf[:10], 11) == cpf[10]
+}
+
+// isValidCNPJ accepts numeric and alphanumeric CNPJs; letters weigh their
+// ASCII code minus 48, which keeps numeric CNPJs on the classic algorithm.
+func isValidCNPJ(cnpj string) bool {
+	if !cnpjPattern.MatchString(cnpj) || strings.Count(cnpj, cnpj[:1]) == 14 {
+		return false
+	}
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	for _, size := range []int{12, 13} {
+		sum := 0
+		for i := 0; i < size; i++ {
+			sum += int(cnpj[i]-'0') * weights[len(weights)-size+i]
+		}
+		digit := 11 - sum%11
+		if digit >= 10 {
+			digit = 0
+		}
+		if byte('0'+digit) != cnpj[size] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDigit computes a CPF check digit with weights starting at weight.
+func checkDigit(digits string, weight int) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[i]-'0') * (weight - i)
+	}
+	digit := sum * 10 % 11
+	if digit == 10 {
+		digit = 0
+	}
+	return byte('0' + digit)
+}
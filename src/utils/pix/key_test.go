@@ -0,0 +1,71 @@
+package pix
+
+import "testing"
+
+func TestDetectKeyType(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "", want: ""},
+		{key: "fulano@example.com", want: KeyTypeEmail},
+		{key: "+55 (11) 91234-5678", want: KeyTypePhone},
+		{key: "123E4567-E12B-12D1-A456-426655440000", want: KeyTypeEVP},
+		{key: "529.982.247-25", want: KeyTypeCPF},
+		{key: "11912345678", want: KeyTypeCPF},
+		{key: "11.222.333/0001-81", want: KeyTypeCNPJ},
+		{key: "12.ABC.345/01DE-35", want: KeyTypeCNPJ},
+		{key: "12345", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := DetectKeyType(tt.key); got != tt.want {
+				t.Errorf("DetectKeyType(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyType string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{name: "formatted cpf", keyType: KeyTypeCPF, key: " 529.982.247-25 ", want: "52998224725"},
+		{name: "cpf with wrong check digit", keyType: KeyTypeCPF, key: "529.982.247-26", wantErr: true},
+		{name: "cpf with repeated digits", keyType: KeyTypeCPF, key: "111.111.111-11", wantErr: true},
+		{name: "numeric cnpj", keyType: KeyTypeCNPJ, key: "11.222.333/0001-81", want: "11222333000181"},
+		{name: "alphanumeric cnpj", keyType: KeyTypeCNPJ, key: "12.abc.345/01de-35", want: "12ABC34501DE35"},
+		{name: "cnpj with wrong check digit", keyType: KeyTypeCNPJ, key: "11.222.333/0001-82", wantErr: true},
+		{name: "email", keyType: KeyTypeEmail, key: "Fulano@Example.com", want: "fulano@example.com"},
+		{name: "email with display name", keyType: KeyTypeEmail, key: "Fulano <fulano@example.com>", wantErr: true},
+		{name: "formatted phone", keyType: KeyTypePhone, key: "+55 (11) 91234-5678", want: "+5511912345678"},
+		{name: "phone without country code", keyType: KeyTypePhone, key: "(11) 3123-4567", want: "+551131234567"},
+		{name: "foreign phone", keyType: KeyTypePhone, key: "+1 202 555 0100", wantErr: true},
+		{name: "evp", keyType: KeyTypeEVP, key: "123E4567-E12B-12D1-A456-426655440000", want: "123e4567-e12b-12d1-a456-426655440000"},
+		{name: "evp without dashes", keyType: KeyTypeEVP, key: "123e4567e12b12d1a456426655440000", wantErr: true},
+		{name: "unknown type", keyType: "iban", key: "52998224725", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeKey(tt.keyType, tt.key)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NormalizeKey(%q, %q) = %q, want an error", tt.keyType, tt.key, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeKey(%q, %q) error = %v", tt.keyType, tt.key, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeKey(%q, %q) = %q, want %q", tt.keyType, tt.key, got, tt.want)
+			}
+		})
+	}
+}